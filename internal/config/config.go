@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Port   string `mapstructure:"PORT"`
+	DBConn string `mapstructure:"DB_CONN"`
+
+	// pajak (PPN) dalam persen, TAX_INCLUSIVE=true jika harga product sudah termasuk pajak
+	TaxRate      float64 `mapstructure:"TAX_RATE"`
+	TaxInclusive bool    `mapstructure:"TAX_INCLUSIVE"`
+
+	// service charge dalam persen, 0 = tidak dipakai
+	ServiceChargeRate float64 `mapstructure:"SERVICE_CHARGE_RATE"`
+
+	// format nomor invoice: PREFIX[/OUTLET]/YYYY/MM[/DD]/NNNN,
+	// INVOICE_RESET=daily (default) atau monthly
+	InvoicePrefix string `mapstructure:"INVOICE_PREFIX"`
+	OutletCode    string `mapstructure:"OUTLET_CODE"`
+	InvoiceReset  string `mapstructure:"INVOICE_RESET"`
+
+	// lama keranjang di-hold sebelum otomatis expired, mis. 30m atau 2h (default 2h)
+	HeldCartTTL time.Duration `mapstructure:"HELD_CART_TTL"`
+
+	// identitas toko untuk struk
+	StoreName     string `mapstructure:"STORE_NAME"`
+	StoreAddress  string `mapstructure:"STORE_ADDRESS"`
+	StorePhone    string `mapstructure:"STORE_PHONE"`
+	StoreTaxID    string `mapstructure:"STORE_TAX_ID"`
+	ReceiptFooter string `mapstructure:"RECEIPT_FOOTER"`
+
+	// lebar struk dalam karakter (32 untuk printer 58mm, 48 untuk 80mm)
+	ReceiptWidth int `mapstructure:"RECEIPT_WIDTH"`
+
+	// isi file text/template untuk struk, kosong = template bawaan
+	ReceiptTemplate string
+}
+
+// Load membaca konfigurasi dari environment dan file .env (jika ada)
+func Load() (Config, error) {
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	// load .env if exists
+	if _, err := os.Stat(".env"); err == nil {
+		viper.SetConfigFile(".env")
+		viper.SetConfigType("env")
+		if err := viper.ReadInConfig(); err != nil {
+			return Config{}, err
+		}
+	}
+
+	cfg := Config{
+		Port:   viper.GetString("PORT"),
+		DBConn: viper.GetString("DB_CONN"),
+
+		TaxRate:      viper.GetFloat64("TAX_RATE"),
+		TaxInclusive: viper.GetBool("TAX_INCLUSIVE"),
+
+		ServiceChargeRate: viper.GetFloat64("SERVICE_CHARGE_RATE"),
+
+		InvoicePrefix: viper.GetString("INVOICE_PREFIX"),
+		OutletCode:    viper.GetString("OUTLET_CODE"),
+		InvoiceReset:  viper.GetString("INVOICE_RESET"),
+
+		HeldCartTTL: viper.GetDuration("HELD_CART_TTL"),
+
+		StoreName:     viper.GetString("STORE_NAME"),
+		StoreAddress:  viper.GetString("STORE_ADDRESS"),
+		StorePhone:    viper.GetString("STORE_PHONE"),
+		StoreTaxID:    viper.GetString("STORE_TAX_ID"),
+		ReceiptFooter: viper.GetString("RECEIPT_FOOTER"),
+		ReceiptWidth:  viper.GetInt("RECEIPT_WIDTH"),
+	}
+
+	if cfg.Port == "" {
+		cfg.Port = "8081"
+	}
+
+	// rate di luar 0-100 membuat total setiap transaksi salah, tolak saat startup
+	for _, rate := range []struct {
+		name  string
+		value float64
+	}{
+		{"TAX_RATE", cfg.TaxRate},
+		{"SERVICE_CHARGE_RATE", cfg.ServiceChargeRate},
+	} {
+		if rate.value < 0 || rate.value > 100 {
+			return Config{}, fmt.Errorf("invalid %s %v, must be between 0 and 100", rate.name, rate.value)
+		}
+	}
+
+	if cfg.InvoicePrefix == "" {
+		cfg.InvoicePrefix = "INV"
+	}
+
+	switch cfg.InvoiceReset {
+	case "":
+		cfg.InvoiceReset = "daily"
+	case "daily", "monthly":
+	default:
+		return Config{}, fmt.Errorf("invalid INVOICE_RESET %q, must be daily or monthly", cfg.InvoiceReset)
+	}
+
+	if cfg.HeldCartTTL <= 0 {
+		cfg.HeldCartTTL = 2 * time.Hour
+	}
+
+	if cfg.StoreName == "" {
+		cfg.StoreName = "Kasir"
+	}
+
+	if cfg.ReceiptWidth <= 0 {
+		cfg.ReceiptWidth = 32
+	}
+
+	if path := viper.GetString("RECEIPT_TEMPLATE_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.ReceiptTemplate = string(content)
+	}
+
+	return cfg, nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
 	"kasir-api/internal/services"
 	"net/http"
 	"strconv"
@@ -105,6 +106,38 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(category)
 }
 
+// GetCategoryProducts godoc
+// @Summary      Get products by category
+// @Description  Ambil semua product dalam satu category
+// @Tags         Categories
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Success      200  {array}   models.Product
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /categories/{id}/products [get]
+func (h *CategoryHandler) GetCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	id, err := getCategoryId(strings.TrimSuffix(r.URL.Path, "/products"))
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	products, err := h.service.GetProducts(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Category not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
 // UpdateCategoryByID godoc
 // @Summary      Update category
 // @Description  Update data category berdasarkan ID
@@ -147,13 +180,15 @@ func (h *CategoryHandler) UpdateCategoryByID(w http.ResponseWriter, r *http.Requ
 
 // DeleteCategoryByID godoc
 // @Summary      Delete category
-// @Description  Hapus category berdasarkan ID
+// @Description  Hapus category berdasarkan ID. Ditolak jika masih ada product, kecuali reassign_to diisi
 // @Tags         Categories
 // @Produce      json
-// @Param        id path int true "Category ID"
+// @Param        id          path  int true  "Category ID"
+// @Param        reassign_to query int false "Pindahkan product ke category ini sebelum dihapus"
 // @Success      200 {object} map[string]string
 // @Failure      400 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Router       /categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategoryByID(w http.ResponseWriter, r *http.Request) {
@@ -163,11 +198,28 @@ func (h *CategoryHandler) DeleteCategoryByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	reassignTo := 0
+	if v := r.URL.Query().Get("reassign_to"); v != "" {
+		reassignTo, err = strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid reassign_to category ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.service.Delete(id, reassignTo); err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Category not found", http.StatusNotFound)
 			return
 		}
+		if err == repository.ErrInvalidReassignCategory {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == repository.ErrCategoryInUse {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
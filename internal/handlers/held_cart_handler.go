@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+)
+
+type HeldCartHandler struct {
+	service *services.HeldCartService
+}
+
+func NewHeldCartHandler(service *services.HeldCartService) *HeldCartHandler {
+	return &HeldCartHandler{
+		service: service,
+	}
+}
+
+// GetHeldCarts godoc
+// @Summary      Get held carts
+// @Description  Ambil daftar keranjang yang di-hold, bisa difilter per kasir dan status
+// @Tags         Held Carts
+// @Produce      json
+// @Param        cashier query string false "Nama / ID kasir"
+// @Param        status  query string false "Status (held, resumed, expired)"
+// @Success      200 {array} models.HeldCart
+// @Failure      500 {object} map[string]string
+// @Router       /held-carts [get]
+func (h *HeldCartHandler) GetHeldCarts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	carts, err := h.service.GetAll(q.Get("cashier"), q.Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(carts)
+}
+
+// CreateHeldCart godoc
+// @Summary      Hold cart
+// @Description  Simpan keranjang untuk dilanjutkan nanti. Jika reserve_stock true, stok item direservasi selama keranjang di-hold
+// @Tags         Held Carts
+// @Accept       json
+// @Produce      json
+// @Param        cart body models.HeldCartRequest true "Held cart payload"
+// @Success      201 {object} models.HeldCart
+// @Failure      400 {object} map[string]string "Invalid request body / product not found"
+// @Failure      409 {object} map[string]string "Label already used / stock not available to reserve"
+// @Failure      500 {object} map[string]string
+// @Router       /held-carts [post]
+func (h *HeldCartHandler) CreateHeldCart(w http.ResponseWriter, r *http.Request) {
+	var req models.HeldCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.service.Create(req)
+	if err != nil {
+		writeHeldCartError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cart)
+}
+
+// GetHeldCartByID godoc
+// @Summary      Get held cart by ID
+// @Description  Ambil detail keranjang yang di-hold
+// @Tags         Held Carts
+// @Produce      json
+// @Param        id path int true "Held cart ID"
+// @Success      200 {object} models.HeldCart
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /held-carts/{id} [get]
+func (h *HeldCartHandler) GetHeldCartByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getHeldCartId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid held cart ID", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.service.GetByID(id)
+	if err != nil {
+		writeHeldCartError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// UpdateHeldCartByID godoc
+// @Summary      Update held cart
+// @Description  Ganti isi keranjang yang masih di-hold, masa hold diperpanjang dari sekarang
+// @Tags         Held Carts
+// @Accept       json
+// @Produce      json
+// @Param        id   path int                    true "Held cart ID"
+// @Param        cart body models.HeldCartRequest true "Held cart payload"
+// @Success      200 {object} models.HeldCart
+// @Failure      400 {object} map[string]string "Invalid request body / product not found"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Held cart not active / label already used / stock not available"
+// @Failure      500 {object} map[string]string
+// @Router       /held-carts/{id} [put]
+func (h *HeldCartHandler) UpdateHeldCartByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getHeldCartId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid held cart ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.HeldCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.service.Update(id, req)
+	if err != nil {
+		writeHeldCartError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// ExpireHeldCartByID godoc
+// @Summary      Expire held cart
+// @Description  Lepas keranjang yang di-hold (dan reservasi stoknya) tanpa checkout
+// @Tags         Held Carts
+// @Produce      json
+// @Param        id path int true "Held cart ID"
+// @Success      200 {object} models.HeldCart
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Held cart not active"
+// @Failure      500 {object} map[string]string
+// @Router       /held-carts/{id} [delete]
+func (h *HeldCartHandler) ExpireHeldCartByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getHeldCartId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid held cart ID", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.service.Expire(id)
+	if err != nil {
+		writeHeldCartError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// ResumeHeldCart godoc
+// @Summary      Resume held cart
+// @Description  Lanjutkan keranjang yang di-hold menjadi transaksi (checkout) dengan pembayaran yang diberikan
+// @Tags         Held Carts
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                          true "Held cart ID"
+// @Param        request body models.ResumeHeldCartRequest true "Pembayaran"
+// @Success      200 {object} models.Transaction
+// @Failure      400 {object} map[string]string "Invalid request body / payment"
+// @Failure      404 {object} map[string]string "Held cart not found / product not found"
+// @Failure      409 {object} map[string]string "Held cart not active / insufficient stock"
+// @Failure      422 {object} map[string]string "Payment does not cover total amount / voucher rejected"
+// @Failure      500 {object} map[string]string
+// @Router       /held-carts/{id}/resume [post]
+func (h *HeldCartHandler) ResumeHeldCart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getHeldCartId(strings.TrimSuffix(r.URL.Path, "/resume"))
+	if err != nil {
+		http.Error(w, "Invalid held cart ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ResumeHeldCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.Resume(id, req)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, "Held cart not found", http.StatusNotFound)
+		case err == repository.ErrHeldCartNotActive:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeCheckoutError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+func writeHeldCartError(w http.ResponseWriter, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		http.Error(w, "Held cart not found", http.StatusNotFound)
+	case err == services.ErrInvalidHeldCart, errors.Is(err, repository.ErrHeldCartProductNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err == repository.ErrHeldCartNotActive, err == repository.ErrHeldCartLabelExists,
+		errors.Is(err, repository.ErrHeldCartStockUnavailable):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// helper
+func getHeldCartId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/held-carts/")
+	return strconv.Atoi(idStr)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/services"
+)
+
+type InventoryHandler struct {
+	service *services.InventoryService
+}
+
+func NewInventoryHandler(service *services.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		service: service,
+	}
+}
+
+// GetLowStock godoc
+// @Summary      Get low stock products
+// @Description  Product dengan min_stock > 0 yang stok tersedianya (stok dikurangi reservasi held cart) sudah di bawah atau sama dengan min_stock, paling kritis lebih dulu. on_order adalah qty yang masih dipesan di PO
+// @Tags         Inventory
+// @Produce      json
+// @Success      200 {array} models.LowStockItem
+// @Failure      405 {object} map[string]string "Method not allowed"
+// @Failure      500 {object} map[string]string
+// @Router       /inventory/low-stock [get]
+func (h *InventoryHandler) GetLowStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	items, err := h.service.GetLowStock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// GetReorderSuggestions godoc
+// @Summary      Get reorder suggestions
+// @Description  Saran qty order per supplier dari rata-rata penjualan harian (transaction_details, dikurangi refund) selama days hari terakhir. Target stok = kebutuhan cover_days hari (minimal min_stock) dikurangi stok tersedia dan qty yang masih dipesan, dibulatkan ke kelipatan reorder_qty
+// @Tags         Inventory
+// @Produce      json
+// @Param        days        query int false "Jendela penjualan dalam hari (default 30, max 365)"
+// @Param        cover_days  query int false "Target stok cukup untuk berapa hari (default 14)"
+// @Param        supplier_id query int false "Filter supplier"
+// @Success      200 {object} models.ReorderReport
+// @Failure      400 {object} map[string]string "Invalid filter"
+// @Failure      405 {object} map[string]string "Method not allowed"
+// @Failure      500 {object} map[string]string
+// @Router       /inventory/reorder-suggestions [get]
+func (h *InventoryHandler) GetReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	var filter models.ReorderFilter
+
+	for _, p := range []struct {
+		name string
+		dest *int
+	}{
+		{"days", &filter.Days},
+		{"cover_days", &filter.CoverDays},
+		{"supplier_id", &filter.SupplierID},
+	} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s", p.name), http.StatusBadRequest)
+			return
+		}
+		*p.dest = n
+	}
+
+	report, err := h.service.GetReorderSuggestions(filter)
+	if err != nil {
+		if err == services.ErrInvalidReorderFilter {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
 	"kasir-api/internal/services"
 	"net/http"
 	"strconv"
@@ -25,7 +26,10 @@ func NewProductHandler(service *services.ProductService) *ProductHandler {
 // @Description  Ambil semua data product
 // @Tags         Products
 // @Produce      json
+// @Param        name        query string false "Filter nama product"
+// @Param        category_id query int    false "Filter category ID"
 // @Success      200 {array} models.Product
+// @Failure      400 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Router       /products [get]
 func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +39,18 @@ func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	name := r.URL.Query().Get("name")
-	products, err := h.service.GetAll(name)
+
+	categoryID := 0
+	if v := r.URL.Query().Get("category_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid category ID", http.StatusBadRequest)
+			return
+		}
+		categoryID = id
+	}
+
+	products, err := h.service.GetAll(name, categoryID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -65,6 +80,12 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 
 	product, err := h.service.Create(payload)
 	if err != nil {
+		if err == services.ErrCategoryNotFound || err == services.ErrInvalidTaxClass ||
+			err == services.ErrSupplierNotFound || err == services.ErrInvalidStockLevel ||
+			err == services.ErrInvalidCostPrice {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -108,7 +129,7 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 
 // UpdateProductByID godoc
 // @Summary      Update product
-// @Description  Update data product berdasarkan ID
+// @Description  Update data product berdasarkan ID. Field stock diabaikan, ubah stok lewat /products/{id}/stock-adjustments
 // @Tags         Products
 // @Accept       json
 // @Produce      json
@@ -134,6 +155,12 @@ func (h *ProductHandler) UpdateProductByID(w http.ResponseWriter, r *http.Reques
 
 	updated, err := h.service.Update(id, payload)
 	if err != nil {
+		if err == services.ErrCategoryNotFound || err == services.ErrInvalidTaxClass ||
+			err == services.ErrSupplierNotFound || err == services.ErrInvalidStockLevel ||
+			err == services.ErrInvalidCostPrice {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err == sql.ErrNoRows {
 			http.Error(w, "Product not found", http.StatusNotFound)
 			return
@@ -148,13 +175,14 @@ func (h *ProductHandler) UpdateProductByID(w http.ResponseWriter, r *http.Reques
 
 // DeleteProductByID godoc
 // @Summary      Delete product
-// @Description  Hapus product berdasarkan ID
+// @Description  Hapus product berdasarkan ID. Product yang sudah punya kartu stok (atau masih dipakai held cart, promo, PO) tidak bisa dihapus
 // @Tags         Products
 // @Produce      json
 // @Param        id path int true "Product ID"
 // @Success      200 {object} map[string]string
 // @Failure      400 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Product has stock history"
 // @Failure      500 {object} map[string]string
 // @Router       /products/{id} [delete]
 func (h *ProductHandler) DeleteProductByID(w http.ResponseWriter, r *http.Request) {
@@ -169,6 +197,10 @@ func (h *ProductHandler) DeleteProductByID(w http.ResponseWriter, r *http.Reques
 			http.Error(w, "Product not found", http.StatusNotFound)
 			return
 		}
+		if err == repository.ErrProductInUse {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
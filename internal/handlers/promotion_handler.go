@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PromotionHandler struct {
+	service *services.PromotionService
+}
+
+func NewPromotionHandler(service *services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		service: service,
+	}
+}
+
+// GetPromotions godoc
+// @Summary      Get all promotions
+// @Description  Ambil semua data promo
+// @Tags         Promotions
+// @Produce      json
+// @Success      200 {array} models.Promotion
+// @Failure      500 {object} map[string]string
+// @Router       /promotions [get]
+func (h *PromotionHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+// CreatePromotion godoc
+// @Summary      Create new promotion
+// @Description  Tambah promo baru (price, buy_x_get_y, bundle)
+// @Tags         Promotions
+// @Accept       json
+// @Produce      json
+// @Param        promotion body models.Promotion true "Create promotion payload"
+// @Success      201 {object} models.Promotion
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /promotions [post]
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var payload models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := h.service.Create(payload)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPromotion) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// GetPromotionByID godoc
+// @Summary      Get promotion by ID
+// @Description  Ambil detail promo berdasarkan ID
+// @Tags         Promotions
+// @Produce      json
+// @Param        id   path      int  true  "Promotion ID"
+// @Success      200  {object}  models.Promotion
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /promotions/{id} [get]
+func (h *PromotionHandler) GetPromotionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getPromotionId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := h.service.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Promotion not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// UpdatePromotionByID godoc
+// @Summary      Update promotion
+// @Description  Update data promo berdasarkan ID
+// @Tags         Promotions
+// @Accept       json
+// @Produce      json
+// @Param        id        path int              true "Promotion ID"
+// @Param        promotion body models.Promotion true "Update promotion payload"
+// @Success      200 {object} models.Promotion
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getPromotionId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.Update(id, payload)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPromotion) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Promotion not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeletePromotionByID godoc
+// @Summary      Delete promotion
+// @Description  Hapus promo berdasarkan ID. Promo yang sudah dipakai transaksi harus dinonaktifkan
+// @Tags         Promotions
+// @Produce      json
+// @Param        id path int true "Promotion ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getPromotionId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Promotion not found", http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrPromotionInUse {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Promotion deleted successfully",
+	})
+}
+
+// helper
+func getPromotionId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/promotions/")
+	return strconv.Atoi(idStr)
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+)
+
+type PurchaseOrderHandler struct {
+	service *services.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service *services.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		service: service,
+	}
+}
+
+// GetPurchaseOrders godoc
+// @Summary      Get purchase orders
+// @Description  Ambil daftar purchase order (tanpa baris), bisa difilter per supplier dan status
+// @Tags         Purchase Orders
+// @Produce      json
+// @Param        supplier_id query int    false "Supplier ID"
+// @Param        status      query string false "Status (draft, sent, partially_received, received, cancelled)"
+// @Success      200 {array} models.PurchaseOrder
+// @Failure      400 {object} map[string]string "Invalid filter"
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter := models.PurchaseOrderFilter{
+		Status: q.Get("status"),
+	}
+
+	if v := q.Get("supplier_id"); v != "" {
+		supplierID, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid supplier_id", http.StatusBadRequest)
+			return
+		}
+		filter.SupplierID = supplierID
+	}
+
+	orders, err := h.service.GetAll(filter)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// CreatePurchaseOrder godoc
+// @Summary      Create purchase order
+// @Description  Buat purchase order baru berstatus draft, satu baris per product
+// @Tags         Purchase Orders
+// @Accept       json
+// @Produce      json
+// @Param        request body models.PurchaseOrderRequest true "Purchase order payload"
+// @Success      201 {object} models.PurchaseOrder
+// @Failure      400 {object} map[string]string "Invalid request body / supplier or product not found"
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Create(req)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
+
+// GetPurchaseOrderByID godoc
+// @Summary      Get purchase order by ID
+// @Description  Ambil detail purchase order beserta baris dan riwayat penerimaan barang
+// @Tags         Purchase Orders
+// @Produce      json
+// @Param        id path int true "Purchase order ID"
+// @Success      200 {object} models.PurchaseOrder
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getPurchaseOrderId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.GetByID(id)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// UpdatePurchaseOrderByID godoc
+// @Summary      Update purchase order
+// @Description  Ganti supplier, catatan dan baris purchase order yang masih draft
+// @Tags         Purchase Orders
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                         true "Purchase order ID"
+// @Param        request body models.PurchaseOrderRequest true "Purchase order payload"
+// @Success      200 {object} models.PurchaseOrder
+// @Failure      400 {object} map[string]string "Invalid request body / supplier or product not found"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Purchase order is not draft"
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getPurchaseOrderId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Update(id, req)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// SendPurchaseOrder godoc
+// @Summary      Send purchase order
+// @Description  Tandai purchase order draft sudah dikirim ke supplier, setelah ini baris tidak bisa diubah
+// @Tags         Purchase Orders
+// @Produce      json
+// @Param        id path int true "Purchase order ID"
+// @Success      200 {object} models.PurchaseOrder
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Purchase order is not draft"
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) SendPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, "/send", h.service.Send)
+}
+
+// CancelPurchaseOrder godoc
+// @Summary      Cancel purchase order
+// @Description  Batalkan purchase order draft atau sent yang belum ada penerimaan barangnya
+// @Tags         Purchase Orders
+// @Produce      json
+// @Param        id path int true "Purchase order ID"
+// @Success      200 {object} models.PurchaseOrder
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Purchase order already received or cancelled"
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, "/cancel", h.service.Cancel)
+}
+
+func (h *PurchaseOrderHandler) changeStatus(w http.ResponseWriter, r *http.Request, suffix string, change func(int) (models.PurchaseOrder, error)) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getPurchaseOrderId(strings.TrimSuffix(r.URL.Path, suffix))
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	order, err := change(id)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary      Receive goods
+// @Description  Catat penerimaan barang (boleh sebagian) untuk purchase order sent / partially_received. Stok product bertambah, harga beli aktual dicatat (default unit_cost di PO) dan status PO diperbarui
+// @Tags         Purchase Orders
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                        true "Purchase order ID"
+// @Param        request body models.GoodsReceiptRequest true "Barang yang diterima"
+// @Success      201 {object} models.PurchaseOrder
+// @Failure      400 {object} map[string]string "Invalid request body / product not in purchase order"
+// @Failure      404 {object} map[string]string "Purchase order not found"
+// @Failure      409 {object} map[string]string "Purchase order not sent / received quantity exceeds quantity ordered"
+// @Failure      500 {object} map[string]string
+// @Router       /purchase-orders/{id}/receipts [post]
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getPurchaseOrderId(strings.TrimSuffix(r.URL.Path, "/receipts"))
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.GoodsReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Receive(id, req)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
+
+func writePurchaseOrderError(w http.ResponseWriter, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		http.Error(w, "Purchase order not found", http.StatusNotFound)
+	case err == services.ErrInvalidPurchaseOrder, err == services.ErrInvalidPurchaseOrderFilter,
+		err == services.ErrInvalidGoodsReceipt, err == repository.ErrPurchaseOrderSupplierNotFound,
+		errors.Is(err, repository.ErrPurchaseOrderProductNotFound),
+		errors.Is(err, repository.ErrReceiptProductNotInOrder):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrPurchaseOrderStatus), errors.Is(err, repository.ErrReceiptQuantityExceeded):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// helper
+func getPurchaseOrderId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/purchase-orders/")
+	return strconv.Atoi(idStr)
+}
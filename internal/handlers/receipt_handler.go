@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"kasir-api/internal/services"
+)
+
+type ReceiptHandler struct {
+	service *services.ReceiptService
+}
+
+func NewReceiptHandler(service *services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{service: service}
+}
+
+// GetReceipt godoc
+// @Summary      Get transaction receipt
+// @Description  Cetak struk transaksi dalam format teks, ESC/POS (printer thermal) atau PDF
+// @Tags         Transactions
+// @Produce      plain
+// @Produce      octet-stream
+// @Produce      application/pdf
+// @Param        id     path  int    true  "Transaction ID"
+// @Param        format query string false "Receipt format" Enums(text, escpos, pdf)
+// @Success      200 {string} string "Receipt content"
+// @Failure      400 {object} map[string]string "Invalid transaction ID / format"
+// @Failure      404 {object} map[string]string "Transaction not found"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /transactions/{id}/receipt [get]
+func (h *ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getTransactionId(strings.TrimSuffix(r.URL.Path, "/receipt"))
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	content, contentType, err := h.service.Render(id, r.URL.Query().Get("format"))
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+		case err == services.ErrInvalidReceiptFormat:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(content)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+)
+
+type RefundHandler struct {
+	service *services.RefundService
+}
+
+func NewRefundHandler(service *services.RefundService) *RefundHandler {
+	return &RefundHandler{service: service}
+}
+
+// CreateRefund godoc
+// @Summary      Refund transaction
+// @Description  Refund / retur sebagian atau seluruh item transaksi dan kembalikan stok
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id              path   int                  true  "Transaction ID"
+// @Param        Idempotency-Key header string               false "Key unik per refund untuk retry yang aman"
+// @Param        request         body   models.RefundRequest true  "Refund items"
+// @Success      201 {object} models.Refund
+// @Failure      400 {object} map[string]string "Invalid request body"
+// @Failure      404 {object} map[string]string "Transaction not found"
+// @Failure      409 {object} map[string]string "Refund quantity exceeds quantity sold / transaction voided"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /transactions/{id}/refund [post]
+func (h *RefundHandler) CreateRefund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getTransactionId(strings.TrimSuffix(r.URL.Path, "/refund"))
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := h.service.Refund(id, req)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+		case err == services.ErrInvalidRefundRequest, errors.Is(err, repository.ErrInvalidRefundItem):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrRefundQuantityExceeded), err == repository.ErrTransactionVoided:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
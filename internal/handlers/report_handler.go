@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"kasir-api/internal/services"
 	"net/http"
+	"time"
 )
 
 type ReportHandler struct {
@@ -12,7 +14,7 @@ type ReportHandler struct {
 
 // GetTodaySalesReport godoc
 // @Summary      Sales report hari ini
-// @Description  Menampilkan total revenue, total transaksi, dan produk terlaris hari ini
+// @Description  Menampilkan gross revenue, total diskon, total revenue nett (gross - diskon, tanpa service charge dan pajak, bersih dari refund), total refund, total transaksi, produk terlaris, dan rincian pembayaran per metode hari ini
 // @Tags         Reports
 // @Accept       json
 // @Produce      json
@@ -40,3 +42,115 @@ func GetTodaySalesReport(service *services.ReportService) http.HandlerFunc {
 		_ = json.NewEncoder(w).Encode(report)
 	}
 }
+
+// GetTaxSummaryReport godoc
+// @Summary      Tax summary report
+// @Description  Ringkasan PPN dan service charge per periode (default hari ini), transaksi void tidak dihitung
+// @Tags         Reports
+// @Produce      json
+// @Param        start_date query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param        end_date   query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Success      200 {object} models.TaxSummary
+// @Failure      400 {object} map[string]string "Invalid date"
+// @Failure      405 {object} map[string]string "Method not allowed"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /report/tax [get]
+func GetTaxSummaryReport(service *services.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		start, end, err := parseDateRange(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		summary, err := service.GetTaxSummary(start, end)
+		if err != nil {
+			if err == services.ErrInvalidDateRange {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(summary)
+	}
+}
+
+// GetProfitReport godoc
+// @Summary      Profit report
+// @Description  Revenue (nett setelah diskon, tanpa pajak dan service charge), COGS dari harga pokok yang di-snapshot saat checkout, laba kotor dan margin per hari, product atau category. Default hari ini, qty yang di-refund dan transaksi void tidak dihitung
+// @Tags         Reports
+// @Produce      json
+// @Param        start_date query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param        end_date   query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Param        group_by   query string false "day (default), product atau category"
+// @Success      200 {object} models.ProfitReport
+// @Failure      400 {object} map[string]string "Invalid date / group_by"
+// @Failure      405 {object} map[string]string "Method not allowed"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /report/profit [get]
+func GetProfitReport(service *services.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		start, end, err := parseDateRange(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := service.GetProfitReport(start, end, r.URL.Query().Get("group_by"))
+		if err != nil {
+			if err == services.ErrInvalidDateRange || err == services.ErrInvalidProfitGroup {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
+
+// parseDateRange membaca start_date dan end_date (YYYY-MM-DD). end_date inklusif,
+// jadi yang dikembalikan adalah awal hari berikutnya.
+func parseDateRange(r *http.Request) (start *time.Time, end *time.Time, err error) {
+	q := r.URL.Query()
+
+	if v := q.Get("start_date"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, nil, errors.New("invalid start_date, use YYYY-MM-DD")
+		}
+		start = &t
+	}
+
+	if v := q.Get("end_date"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, nil, errors.New("invalid end_date, use YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		end = &t
+	}
+
+	return start, end, nil
+}
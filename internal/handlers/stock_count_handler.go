@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+)
+
+type StockCountHandler struct {
+	service *services.StockCountService
+}
+
+func NewStockCountHandler(service *services.StockCountService) *StockCountHandler {
+	return &StockCountHandler{
+		service: service,
+	}
+}
+
+// GetStockCounts godoc
+// @Summary      Get stock counts
+// @Description  Ambil daftar sesi stock opname (tanpa item), terbaru lebih dulu
+// @Tags         Stock Counts
+// @Produce      json
+// @Param        status query string false "Status (open, posted, cancelled)"
+// @Success      200 {array} models.StockCount
+// @Failure      400 {object} map[string]string "Invalid status"
+// @Failure      500 {object} map[string]string
+// @Router       /stock-counts [get]
+func (h *StockCountHandler) GetStockCounts(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.service.GetAll(r.URL.Query().Get("status"))
+	if err != nil {
+		writeStockCountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
+
+// OpenStockCount godoc
+// @Summary      Open stock count
+// @Description  Buka sesi stock opname baru, stok sistem semua product di-snapshot saat ini. Hanya boleh ada satu sesi open
+// @Tags         Stock Counts
+// @Accept       json
+// @Produce      json
+// @Param        request body models.OpenStockCountRequest true "Stock count payload"
+// @Success      201 {object} models.StockCount
+// @Failure      400 {object} map[string]string "Invalid request body"
+// @Failure      409 {object} map[string]string "Another stock count is still open"
+// @Failure      500 {object} map[string]string
+// @Router       /stock-counts [post]
+func (h *StockCountHandler) OpenStockCount(w http.ResponseWriter, r *http.Request) {
+	var req models.OpenStockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.service.Open(req)
+	if err != nil {
+		writeStockCountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(count)
+}
+
+// GetStockCountByID godoc
+// @Summary      Get stock count by ID
+// @Description  Review sesi stock opname: stok sistem, hasil hitung dan selisih tiap product beserta ringkasannya
+// @Tags         Stock Counts
+// @Produce      json
+// @Param        id path int true "Stock count ID"
+// @Success      200 {object} models.StockCount
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /stock-counts/{id} [get]
+func (h *StockCountHandler) GetStockCountByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getStockCountId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid stock count ID", http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.service.GetByID(id)
+	if err != nil {
+		writeStockCountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(count)
+}
+
+// CancelStockCountByID godoc
+// @Summary      Cancel stock count
+// @Description  Batalkan sesi stock opname yang masih open tanpa mengubah stok
+// @Tags         Stock Counts
+// @Produce      json
+// @Param        id path int true "Stock count ID"
+// @Success      200 {object} models.StockCount
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Stock count already posted or cancelled"
+// @Failure      500 {object} map[string]string
+// @Router       /stock-counts/{id} [delete]
+func (h *StockCountHandler) CancelStockCountByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getStockCountId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid stock count ID", http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.service.Cancel(id)
+	if err != nil {
+		writeStockCountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(count)
+}
+
+// SubmitStockCounts godoc
+// @Summary      Submit counted quantities
+// @Description  Kirim hasil hitung fisik, boleh sebagian dan dari beberapa petugas. Hitungan terakhir untuk product yang sama menggantikan yang sebelumnya
+// @Tags         Stock Counts
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                         true "Stock count ID"
+// @Param        request body models.StockCountSubmission true "Hasil hitung"
+// @Success      200 {object} models.StockCount
+// @Failure      400 {object} map[string]string "Invalid request body / product not in stock count"
+// @Failure      404 {object} map[string]string "Stock count not found"
+// @Failure      409 {object} map[string]string "Stock count already posted or cancelled"
+// @Failure      500 {object} map[string]string
+// @Router       /stock-counts/{id}/counts [post]
+func (h *StockCountHandler) SubmitStockCounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getStockCountId(strings.TrimSuffix(r.URL.Path, "/counts"))
+	if err != nil {
+		http.Error(w, "Invalid stock count ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StockCountSubmission
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.service.SubmitCounts(id, req)
+	if err != nil {
+		writeStockCountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(count)
+}
+
+// PostStockCount godoc
+// @Summary      Post stock count
+// @Description  Terapkan selisih (counted - system) product yang sudah dihitung ke stok saat ini, catat di kartu stok sebagai opname dan tutup sesi dalam satu DB transaction
+// @Tags         Stock Counts
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                          true "Stock count ID"
+// @Param        request body models.PostStockCountRequest true "Petugas yang memposting"
+// @Success      200 {object} models.StockCount
+// @Failure      400 {object} map[string]string "Invalid request body"
+// @Failure      404 {object} map[string]string "Stock count not found"
+// @Failure      409 {object} map[string]string "Stock count already posted or cancelled / stock cannot go below zero"
+// @Failure      500 {object} map[string]string
+// @Router       /stock-counts/{id}/post [post]
+func (h *StockCountHandler) PostStockCount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getStockCountId(strings.TrimSuffix(r.URL.Path, "/post"))
+	if err != nil {
+		http.Error(w, "Invalid stock count ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PostStockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.service.Post(id, req)
+	if err != nil {
+		writeStockCountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(count)
+}
+
+func writeStockCountError(w http.ResponseWriter, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		http.Error(w, "Stock count not found", http.StatusNotFound)
+	case err == services.ErrInvalidStockCount, err == services.ErrInvalidStockCountStatus,
+		err == services.ErrInvalidStockCountItems, err == services.ErrInvalidStockCountPost,
+		errors.Is(err, repository.ErrStockCountItemNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err == repository.ErrStockCountNotOpen, err == repository.ErrStockCountAlreadyOpen,
+		errors.Is(err, repository.ErrNegativeStock):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// helper
+func getStockCountId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/stock-counts/")
+	return strconv.Atoi(idStr)
+}
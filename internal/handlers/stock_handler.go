@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+)
+
+type StockHandler struct {
+	service *services.StockService
+}
+
+func NewStockHandler(service *services.StockService) *StockHandler {
+	return &StockHandler{
+		service: service,
+	}
+}
+
+// GetStockMovements godoc
+// @Summary      Get product stock movements
+// @Description  Kartu stok product: setiap perubahan stok (sale, refund, void, adjustment, receipt, opname) beserta saldo setelahnya, terbaru lebih dulu
+// @Tags         Stock
+// @Produce      json
+// @Param        id         path  int    true  "Product ID"
+// @Param        start_date query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param        end_date   query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Param        reason     query string false "Filter alasan perubahan"
+// @Param        page       query int    false "Halaman (default 1)"
+// @Param        limit      query int    false "Jumlah per halaman (default 50, max 200)"
+// @Success      200 {object} models.StockMovementList
+// @Failure      400 {object} map[string]string "Invalid product ID / filter"
+// @Failure      404 {object} map[string]string "Product not found"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /products/{id}/stock-movements [get]
+func (h *StockHandler) GetStockMovements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getProductId(strings.TrimSuffix(r.URL.Path, "/stock-movements"))
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := models.StockMovementFilter{
+		ProductID: id,
+		StartDate: start,
+		EndDate:   end,
+		Reason:    r.URL.Query().Get("reason"),
+	}
+
+	for _, p := range []struct {
+		name string
+		dest *int
+	}{
+		{"page", &filter.Page},
+		{"limit", &filter.Limit},
+	} {
+		v := r.URL.Query().Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("invalid %s", p.name), http.StatusBadRequest)
+			return
+		}
+		*p.dest = n
+	}
+
+	movements, err := h.service.GetMovements(filter)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			http.Error(w, "Product not found", http.StatusNotFound)
+		case services.ErrInvalidStockMovementFilter:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
+
+// CreateStockAdjustment godoc
+// @Summary      Adjust product stock
+// @Description  Penyesuaian stok relatif terhadap stok saat ini. damaged, lost dan expired memakai delta negatif, found memakai delta positif. Tercatat di kartu stok
+// @Tags         Stock
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                           true "Product ID"
+// @Param        request body models.StockAdjustmentRequest true "Delta dan alasan"
+// @Success      201 {object} models.StockAdjustment
+// @Failure      400 {object} map[string]string "Invalid request body / reason / delta"
+// @Failure      404 {object} map[string]string "Product not found"
+// @Failure      409 {object} map[string]string "Stock cannot go below zero"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /products/{id}/stock-adjustments [post]
+func (h *StockHandler) CreateStockAdjustment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getProductId(strings.TrimSuffix(r.URL.Path, "/stock-adjustments"))
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	adjustment, err := h.service.Adjust(id, req)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, "Product not found", http.StatusNotFound)
+		case err == services.ErrInvalidStockAdjustment:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrNegativeStock):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(adjustment)
+}
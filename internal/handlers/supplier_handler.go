@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SupplierHandler struct {
+	service *services.SupplierService
+}
+
+func NewSupplierHandler(service *services.SupplierService) *SupplierHandler {
+	return &SupplierHandler{
+		service: service,
+	}
+}
+
+// GetSuppliers godoc
+// @Summary      Get all suppliers
+// @Description  Ambil semua data supplier
+// @Tags         Suppliers
+// @Produce      json
+// @Success      200 {array} models.Supplier
+// @Failure      500 {object} map[string]string
+// @Router       /suppliers [get]
+func (h *SupplierHandler) GetSuppliers(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suppliers)
+}
+
+// CreateSupplier godoc
+// @Summary      Create new supplier
+// @Description  Tambah supplier baru
+// @Tags         Suppliers
+// @Accept       json
+// @Produce      json
+// @Param        supplier body models.Supplier true "Create supplier payload"
+// @Success      201 {object} models.Supplier
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /suppliers [post]
+func (h *SupplierHandler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	var payload models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.service.Create(payload)
+	if err != nil {
+		if err == services.ErrInvalidSupplier {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(supplier)
+}
+
+// GetSupplierByID godoc
+// @Summary      Get supplier by ID
+// @Description  Ambil detail supplier berdasarkan ID
+// @Tags         Suppliers
+// @Produce      json
+// @Param        id   path      int  true  "Supplier ID"
+// @Success      200  {object}  models.Supplier
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /suppliers/{id} [get]
+func (h *SupplierHandler) GetSupplierByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getSupplierId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.service.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(supplier)
+}
+
+// UpdateSupplierByID godoc
+// @Summary      Update supplier
+// @Description  Update data supplier berdasarkan ID
+// @Tags         Suppliers
+// @Accept       json
+// @Produce      json
+// @Param        id       path int             true "Supplier ID"
+// @Param        supplier body models.Supplier true "Update supplier payload"
+// @Success      200 {object} models.Supplier
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /suppliers/{id} [put]
+func (h *SupplierHandler) UpdateSupplierByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getSupplierId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.service.Update(id, payload)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+			return
+		}
+		if err == services.ErrInvalidSupplier {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(supplier)
+}
+
+// DeleteSupplierByID godoc
+// @Summary      Delete supplier
+// @Description  Hapus supplier berdasarkan ID. Ditolak jika supplier sudah punya purchase order
+// @Tags         Suppliers
+// @Produce      json
+// @Param        id path int true "Supplier ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /suppliers/{id} [delete]
+func (h *SupplierHandler) DeleteSupplierByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getSupplierId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrSupplierInUse {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Supplier deleted successfully",
+	})
+}
+
+// helper
+func getSupplierId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/suppliers/")
+	return strconv.Atoi(idStr)
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
 	"kasir-api/internal/services"
 )
 
@@ -27,29 +33,301 @@ func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Reque
 
 // Checkout godoc
 // @Summary      Create checkout
-// @Description  Melakukan checkout dan membuat transaksi baru
+// @Description  Melakukan checkout dan membuat transaksi baru. Promo aktif diterapkan otomatis. Pembayaran bisa dipecah ke beberapa metode (cash, debit_card, e_wallet, qris, bank_transfer), kembalian hanya dari cash. Baris dengan product yang sama digabung, field yang tidak dikenal ditolak
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
-// @Param        request body models.CheckoutRequest true "Checkout items"
+// @Param        Idempotency-Key header string false "Key unik per checkout, retry dengan key yang sama tidak membuat transaksi baru"
+// @Param        request body models.CheckoutRequest true "Checkout items, diskon, voucher, dan pembayaran"
 // @Success      200 {object} models.Transaction
-// @Failure      400 {object} map[string]string "Invalid request body"
+// @Failure      400 {object} map[string]string "Invalid request body / payment / discount"
+// @Failure      404 {object} models.CheckoutErrorResponse "Product not found (semua baris yang gagal)"
+// @Failure      409 {object} models.CheckoutErrorResponse "Insufficient stock (semua baris yang gagal) / Idempotency-Key still in progress"
+// @Failure      422 {object} models.CheckoutErrorResponse "Empty cart / invalid quantity / invalid product / conflicting discount, atau payment / voucher rejected / Idempotency-Key reused"
 // @Failure      500 {object} map[string]string "Internal server error"
 // @Router       /checkout [post]
 func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeCheckoutRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.Checkout(req)
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// decodeCheckoutRequest menolak field yang tidak dikenal (mis. typo "qty")
+// supaya tidak diam-diam terbaca sebagai nilai nol
+func decodeCheckoutRequest(r *http.Request) (models.CheckoutRequest, error) {
 	var req models.CheckoutRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&req)
+	return req, err
+}
+
+// writeCheckoutError memetakan error checkout ke status HTTP,
+// dipakai checkout langsung maupun resume held cart
+func writeCheckoutError(w http.ResponseWriter, err error) {
+	var checkoutErr *repository.CheckoutError
+	if errors.As(err, &checkoutErr) {
+		// keranjang tidak valid -> 422, product tidak ada -> 404, sisanya stok kurang -> 409
+		status := http.StatusConflict
+		switch {
+		case checkoutErr.HasReason(models.CheckoutErrorEmptyCart), checkoutErr.HasReason(models.CheckoutErrorInvalidQuantity),
+			checkoutErr.HasReason(models.CheckoutErrorInvalidProduct), checkoutErr.HasReason(models.CheckoutErrorConflictingDiscount):
+			status = http.StatusUnprocessableEntity
+		case checkoutErr.HasReason(models.CheckoutErrorProductNotFound):
+			status = http.StatusNotFound
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(models.CheckoutErrorResponse{
+			Error: checkoutErr.Error(),
+			Items: checkoutErr.Items,
+		})
+		return
+	}
+
+	switch {
+	case err == services.ErrInvalidPayment, err == services.ErrInvalidDiscount, err == services.ErrInvalidCustomerRef:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrInsufficientPayment), errors.Is(err, repository.ErrNonCashOverpayment),
+		repository.IsVoucherError(err):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *TransactionHandler) HandleQuote(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Quote(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Quote godoc
+// @Summary      Quote checkout
+// @Description  Simulasi checkout untuk menampilkan harga per baris, diskon, promo, pajak dan total sebelum transaksi disimpan. Tidak mengunci stok dan tidak menyimpan apa pun. Baris yang product-nya tidak ada atau stoknya kurang dikembalikan di failures, pembayaran tidak diperiksa
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        request body models.CheckoutRequest true "Checkout items, diskon dan voucher"
+// @Success      200 {object} models.CheckoutQuote
+// @Failure      400 {object} map[string]string "Invalid request body / discount"
+// @Failure      422 {object} models.CheckoutErrorResponse "Empty cart / invalid quantity / invalid product / conflicting discount"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /checkout/quote [post]
+func (h *TransactionHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeCheckoutRequest(r)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items)
+	quote, err := h.service.Quote(req)
 	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+func (h *TransactionHandler) HandleTransactions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTransactions(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTransactionByID(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetTransactions godoc
+// @Summary      Get transaction history
+// @Description  Ambil riwayat transaksi dengan filter tanggal, nominal, product, nomor invoice dan pagination
+// @Tags         Transactions
+// @Produce      json
+// @Param        start_date query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param        end_date   query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Param        min_amount query int    false "Total minimum"
+// @Param        max_amount query int    false "Total maksimum"
+// @Param        product_id query int    false "Hanya transaksi yang berisi product ini"
+// @Param        status     query string false "Status transaksi (completed, voided)"
+// @Param        invoice    query string false "Cari nomor invoice (sebagian, mis. 2026/10/18)"
+// @Param        page       query int    false "Halaman (default 1)"
+// @Param        limit      query int    false "Jumlah per halaman (default 20, max 100)"
+// @Success      200 {object} models.TransactionList
+// @Failure      400 {object} map[string]string "Invalid filter"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /transactions [get]
+func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTransactionFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transactions, err := h.service.GetAll(filter)
+	if err != nil {
+		if err == services.ErrInvalidTransactionFilter {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+// GetTransactionByID godoc
+// @Summary      Get transaction by ID
+// @Description  Ambil detail transaksi beserta item-itemnya
+// @Tags         Transactions
+// @Produce      json
+// @Param        id  path  int  true  "Transaction ID"
+// @Success      200 {object} models.Transaction
+// @Failure      400 {object} map[string]string "Invalid transaction ID"
+// @Failure      404 {object} map[string]string "Transaction not found"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /transactions/{id} [get]
+func (h *TransactionHandler) GetTransactionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getTransactionId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// VoidTransaction godoc
+// @Summary      Void transaction
+// @Description  Batalkan transaksi, kembalikan stok, dan catat supervisor serta alasannya
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                true "Transaction ID"
+// @Param        request body models.VoidRequest true "Void payload"
+// @Success      200 {object} models.Transaction
+// @Failure      400 {object} map[string]string "Invalid request body"
+// @Failure      404 {object} map[string]string "Transaction not found"
+// @Failure      409 {object} map[string]string "Transaction already voided"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /transactions/{id}/void [post]
+func (h *TransactionHandler) VoidTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := getTransactionId(strings.TrimSuffix(r.URL.Path, "/void"))
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.VoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.Void(id, req)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+		case services.ErrInvalidVoidRequest:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case repository.ErrTransactionVoided:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(transaction)
 }
+
+// helper
+func getTransactionId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/transactions/")
+	return strconv.Atoi(idStr)
+}
+
+func parseTransactionFilter(r *http.Request) (models.TransactionFilter, error) {
+	var filter models.TransactionFilter
+	q := r.URL.Query()
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.StartDate = start
+	filter.EndDate = end
+
+	filter.Status = q.Get("status")
+	filter.Invoice = strings.TrimSpace(q.Get("invoice"))
+
+	intParams := []struct {
+		name string
+		dest *int
+	}{
+		{"min_amount", &filter.MinAmount},
+		{"max_amount", &filter.MaxAmount},
+		{"product_id", &filter.ProductID},
+		{"page", &filter.Page},
+		{"limit", &filter.Limit},
+	}
+
+	for _, p := range intParams {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid %s", p.name)
+		}
+		*p.dest = n
+	}
+
+	return filter, nil
+}
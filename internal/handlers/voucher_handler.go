@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type VoucherHandler struct {
+	service *services.VoucherService
+}
+
+func NewVoucherHandler(service *services.VoucherService) *VoucherHandler {
+	return &VoucherHandler{
+		service: service,
+	}
+}
+
+// GetVouchers godoc
+// @Summary      Get all vouchers
+// @Description  Ambil semua data voucher beserta jumlah pemakaiannya
+// @Tags         Vouchers
+// @Produce      json
+// @Success      200 {array} models.Voucher
+// @Failure      500 {object} map[string]string
+// @Router       /vouchers [get]
+func (h *VoucherHandler) GetVouchers(w http.ResponseWriter, r *http.Request) {
+	vouchers, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vouchers)
+}
+
+// CreateVoucher godoc
+// @Summary      Create new voucher
+// @Description  Tambah voucher baru
+// @Tags         Vouchers
+// @Accept       json
+// @Produce      json
+// @Param        voucher body models.Voucher true "Create voucher payload"
+// @Success      201 {object} models.Voucher
+// @Failure      400 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /vouchers [post]
+func (h *VoucherHandler) CreateVoucher(w http.ResponseWriter, r *http.Request) {
+	var payload models.Voucher
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	voucher, err := h.service.Create(payload)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidVoucher:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case repository.ErrVoucherCodeExists:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// GetVoucherByID godoc
+// @Summary      Get voucher by ID
+// @Description  Ambil detail voucher berdasarkan ID
+// @Tags         Vouchers
+// @Produce      json
+// @Param        id   path      int  true  "Voucher ID"
+// @Success      200  {object}  models.Voucher
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /vouchers/{id} [get]
+func (h *VoucherHandler) GetVoucherByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getVoucherId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid voucher ID", http.StatusBadRequest)
+		return
+	}
+
+	voucher, err := h.service.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Voucher not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// UpdateVoucherByID godoc
+// @Summary      Update voucher
+// @Description  Update data voucher berdasarkan ID
+// @Tags         Vouchers
+// @Accept       json
+// @Produce      json
+// @Param        id      path int            true "Voucher ID"
+// @Param        voucher body models.Voucher true "Update voucher payload"
+// @Success      200 {object} models.Voucher
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /vouchers/{id} [put]
+func (h *VoucherHandler) UpdateVoucherByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getVoucherId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid voucher ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload models.Voucher
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.Update(id, payload)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			http.Error(w, "Voucher not found", http.StatusNotFound)
+		case services.ErrInvalidVoucher:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case repository.ErrVoucherCodeExists:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteVoucherByID godoc
+// @Summary      Delete voucher
+// @Description  Hapus voucher berdasarkan ID. Voucher yang sudah ditukar harus dinonaktifkan
+// @Tags         Vouchers
+// @Produce      json
+// @Param        id path int true "Voucher ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /vouchers/{id} [delete]
+func (h *VoucherHandler) DeleteVoucherByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getVoucherId(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid voucher ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Voucher not found", http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrVoucherInUse {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Voucher deleted successfully",
+	})
+}
+
+// GetVoucherRedemptionReport godoc
+// @Summary      Voucher redemption report
+// @Description  Jumlah penukaran dan total potongan per voucher (transaksi void tidak dihitung)
+// @Tags         Reports
+// @Produce      json
+// @Success      200 {array} models.VoucherRedemptionReport
+// @Failure      405 {object} map[string]string "Method not allowed"
+// @Failure      500 {object} map[string]string "Internal server error"
+// @Router       /report/vouchers [get]
+func (h *VoucherHandler) GetVoucherRedemptionReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.service.GetRedemptionReport()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// helper
+func getVoucherId(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/v1/vouchers/")
+	return strconv.Atoi(idStr)
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"kasir-api/internal/services"
+)
+
+// body request yang di-buffer untuk hash idempotency dibatasi 1 MB
+const maxIdempotentBodyBytes = 1 << 20
+
+// Idempotency membuat request POST dengan header Idempotency-Key aman di-retry:
+// request pertama diproses dan response sukses-nya disimpan, retry dengan key dan
+// body yang sama mendapat response yang sama tanpa diproses ulang. Response gagal
+// tidak disimpan sehingga retry diproses ulang.
+func Idempotency(next http.Handler, service *services.IdempotencyService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, err := service.Begin(key, r.Method, r.URL.Path, body)
+		if err != nil {
+			switch err {
+			case services.ErrInvalidIdempotencyKey:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case services.ErrIdempotencyKeyReused:
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			case services.ErrIdempotencyKeyInFlight:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// replay response request pertama
+		if record != nil {
+			if record.ContentType != "" {
+				w.Header().Set("Content-Type", record.ContentType)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(*record.StatusCode)
+			w.Write(record.Response)
+			return
+		}
+
+		// lease key diperpanjang selama handler berjalan
+		stopKeepAlive := service.KeepAlive(key)
+		defer stopKeepAlive()
+
+		// jika handler panic (di-recover net/http), key dilepas supaya tidak
+		// tertahan in-flight sampai kedaluwarsa
+		finished := false
+		defer func() {
+			if finished {
+				return
+			}
+			if err := service.Release(key); err != nil {
+				log.Println("failed to release idempotency key:", err)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		finished = true
+
+		if err := service.Finish(key, rec.status, w.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+			log.Println("failed to store idempotency response:", err)
+		}
+	})
+}
+
+// responseRecorder meneruskan response ke client sambil menyimpan salinannya
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,26 @@
+package models
+
+const (
+	CheckoutErrorEmptyCart           = "empty_cart"
+	CheckoutErrorInvalidQuantity     = "invalid_quantity"
+	CheckoutErrorInvalidProduct      = "invalid_product_id"
+	CheckoutErrorConflictingDiscount = "conflicting_discount"
+	CheckoutErrorProductNotFound     = "product_not_found"
+	CheckoutErrorInsufficientStock   = "insufficient_stock"
+)
+
+// CheckoutItemError adalah satu baris keranjang yang membuat checkout gagal.
+// Available hanya berarti untuk reason insufficient_stock.
+type CheckoutItemError struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Quantity    int    `json:"quantity"`
+	Available   int    `json:"available"`
+	Reason      string `json:"reason"`
+}
+
+// CheckoutErrorResponse adalah body response checkout yang gagal karena isi keranjang
+type CheckoutErrorResponse struct {
+	Error string              `json:"error"`
+	Items []CheckoutItemError `json:"items"`
+}
@@ -0,0 +1,48 @@
+package models
+
+const (
+	DiscountTypePercentage = "percentage"
+	DiscountTypeFixed      = "fixed"
+)
+
+// Discount dipakai untuk diskon per item maupun per keranjang.
+// Value berisi persen (0-100) untuk percentage, atau nominal rupiah untuk fixed.
+type Discount struct {
+	Type  string `json:"type"`
+	Value int    `json:"value"`
+}
+
+// Amount menghitung nominal diskon dari base, tidak pernah melebihi base
+func (d *Discount) Amount(base int) int {
+	if d == nil || base <= 0 {
+		return 0
+	}
+
+	amount := 0
+	switch d.Type {
+	case DiscountTypePercentage:
+		amount = base * d.Value / 100
+	case DiscountTypeFixed:
+		amount = d.Value
+	}
+
+	if amount > base {
+		return base
+	}
+	return amount
+}
+
+// IsValid memastikan type dikenal dan value dalam batas
+func (d *Discount) IsValid() bool {
+	if d == nil {
+		return true
+	}
+
+	switch d.Type {
+	case DiscountTypePercentage:
+		return d.Value >= 0 && d.Value <= 100
+	case DiscountTypeFixed:
+		return d.Value >= 0
+	}
+	return false
+}
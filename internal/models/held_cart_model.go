@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	HeldCartStatusHeld    = "held"
+	HeldCartStatusResumed = "resumed"
+	HeldCartStatusExpired = "expired"
+)
+
+// HeldCart adalah keranjang yang diparkir kasir. Jika ReserveStock true,
+// qty item tidak bisa dijual ke keranjang lain selama status held dan belum expired.
+type HeldCart struct {
+	ID            int            `json:"id"`
+	Cashier       string         `json:"cashier"`
+	Label         string         `json:"label,omitempty"`
+	Items         []HeldCartItem `json:"items"`
+	Discount      *Discount      `json:"discount,omitempty"`
+	VoucherCode   string         `json:"voucher_code,omitempty"`
+	CustomerRef   string         `json:"customer_ref,omitempty"`
+	ReserveStock  bool           `json:"reserve_stock"`
+	Status        string         `json:"status"`
+	TransactionID *int           `json:"transaction_id,omitempty"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type HeldCartItem struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	ProductName string    `json:"product_name,omitempty"`
+	Quantity    int       `json:"quantity"`
+	Discount    *Discount `json:"discount,omitempty"`
+}
+
+// HeldCartRequest dipakai untuk membuat dan mengubah held cart
+type HeldCartRequest struct {
+	Cashier      string         `json:"cashier"`
+	Label        string         `json:"label,omitempty"`
+	Items        []CheckoutItem `json:"items"`
+	Discount     *Discount      `json:"discount,omitempty"`
+	VoucherCode  string         `json:"voucher_code,omitempty"`
+	CustomerRef  string         `json:"customer_ref,omitempty"`
+	ReserveStock bool           `json:"reserve_stock"`
+}
+
+// ResumeHeldCartRequest berisi pembayaran saat held cart dilanjutkan ke checkout
+type ResumeHeldCartRequest struct {
+	Payments []CheckoutPayment `json:"payments"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// IdempotencyRecord adalah request yang pernah diproses dengan Idempotency-Key,
+// StatusCode nil berarti request pertama masih diproses
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  *int
+	ContentType string
+	Response    []byte
+	CreatedAt   time.Time
+}
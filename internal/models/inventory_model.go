@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// LowStockItem product dengan stok tersedia (stok dikurangi reservasi held cart)
+// di bawah atau sama dengan MinStock
+type LowStockItem struct {
+	ProductID    int    `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	Stock        int    `json:"stock"`
+	Reserved     int    `json:"reserved"`
+	Available    int    `json:"available"`
+	MinStock     int    `json:"min_stock"`
+	ReorderQty   int    `json:"reorder_qty"`
+	Shortage     int    `json:"shortage"`
+	OnOrder      int    `json:"on_order"`
+	SupplierID   *int   `json:"supplier_id"`
+	SupplierName string `json:"supplier_name,omitempty"`
+}
+
+// ReorderFilter, Days adalah jendela penjualan untuk menghitung rata-rata harian,
+// CoverDays adalah target berapa hari stok harus cukup setelah order diterima
+type ReorderFilter struct {
+	Days       int
+	CoverDays  int
+	SupplierID int
+}
+
+type ReorderSuggestion struct {
+	ProductID     int      `json:"product_id"`
+	ProductName   string   `json:"product_name"`
+	Available     int      `json:"available"`
+	OnOrder       int      `json:"on_order"`
+	MinStock      int      `json:"min_stock"`
+	ReorderQty    int      `json:"reorder_qty"`
+	QtySold       int      `json:"qty_sold"`
+	DailyVelocity float64  `json:"daily_velocity"`
+	DaysOfCover   *float64 `json:"days_of_cover"`
+	SuggestedQty  int      `json:"suggested_qty"`
+	UnitCost      int      `json:"unit_cost"`
+	EstimatedCost int      `json:"estimated_cost"`
+}
+
+// ReorderSupplierGroup saran order untuk satu supplier, SupplierID nil berarti
+// product belum punya supplier
+type ReorderSupplierGroup struct {
+	SupplierID    *int                `json:"supplier_id"`
+	SupplierName  string              `json:"supplier_name,omitempty"`
+	Items         []ReorderSuggestion `json:"items"`
+	EstimatedCost int                 `json:"estimated_cost"`
+}
+
+type ReorderReport struct {
+	Days        int                    `json:"days"`
+	CoverDays   int                    `json:"cover_days"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Suppliers   []ReorderSupplierGroup `json:"suppliers"`
+}
+
+// ReorderCandidate data mentah per product untuk menghitung saran order
+type ReorderCandidate struct {
+	ProductID    int
+	ProductName  string
+	Available    int
+	OnOrder      int
+	MinStock     int
+	ReorderQty   int
+	QtySold      int
+	UnitCost     int
+	SupplierID   *int
+	SupplierName string
+}
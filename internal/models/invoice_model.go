@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	InvoiceResetDaily   = "daily"
+	InvoiceResetMonthly = "monthly"
+)
+
+// InvoiceSettings menentukan format nomor invoice, mis. INV/2026/10/18/0001
+// (reset harian) atau INV/OUT1/2026/10/0001 (reset bulanan dengan kode outlet)
+type InvoiceSettings struct {
+	Prefix     string
+	OutletCode string
+	Reset      string
+}
+
+// Scope adalah bagian nomor invoice sebelum nomor urut, counter nomor urut
+// disimpan per scope sehingga otomatis mulai dari 1 saat hari / bulan berganti
+func (s InvoiceSettings) Scope(t time.Time) string {
+	scope := s.Prefix
+	if s.OutletCode != "" {
+		scope += "/" + s.OutletCode
+	}
+
+	if s.Reset == InvoiceResetMonthly {
+		return scope + t.Format("/2006/01")
+	}
+	return scope + t.Format("/2006/01/02")
+}
+
+func (s InvoiceSettings) Format(scope string, number int) string {
+	return fmt.Sprintf("%s/%04d", scope, number)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvoiceSettingsScope(t *testing.T) {
+	day := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		settings InvoiceSettings
+		want     string
+	}{
+		{"daily", InvoiceSettings{Prefix: "INV", Reset: InvoiceResetDaily}, "INV/2026/10/18"},
+		{"empty reset is daily", InvoiceSettings{Prefix: "INV"}, "INV/2026/10/18"},
+		{"monthly", InvoiceSettings{Prefix: "INV", Reset: InvoiceResetMonthly}, "INV/2026/10"},
+		{"daily with outlet", InvoiceSettings{Prefix: "INV", OutletCode: "OUT1", Reset: InvoiceResetDaily}, "INV/OUT1/2026/10/18"},
+		{"monthly with outlet", InvoiceSettings{Prefix: "INV", OutletCode: "OUT1", Reset: InvoiceResetMonthly}, "INV/OUT1/2026/10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.Scope(day); got != tt.want {
+				t.Fatalf("Scope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// counter disimpan per scope, jadi nomor urut mulai dari 1 tepat saat scope berganti
+func TestInvoiceSettingsScopeReset(t *testing.T) {
+	tests := []struct {
+		name      string
+		reset     string
+		a, b      time.Time
+		sameScope bool
+	}{
+		{"daily same day", InvoiceResetDaily,
+			time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC), true},
+		{"daily next day", InvoiceResetDaily,
+			time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC), time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), false},
+		{"monthly same month", InvoiceResetMonthly,
+			time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 31, 23, 59, 59, 0, time.UTC), true},
+		{"monthly next month", InvoiceResetMonthly,
+			time.Date(2026, 10, 31, 23, 59, 59, 0, time.UTC), time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), false},
+		{"monthly same month next year", InvoiceResetMonthly,
+			time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 10, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := InvoiceSettings{Prefix: "INV", Reset: tt.reset}
+			if got := s.Scope(tt.a) == s.Scope(tt.b); got != tt.sameScope {
+				t.Fatalf("Scope(%v) == Scope(%v) is %v, want %v", tt.a, tt.b, got, tt.sameScope)
+			}
+		})
+	}
+}
+
+func TestInvoiceSettingsFormat(t *testing.T) {
+	s := InvoiceSettings{Prefix: "INV"}
+
+	tests := []struct {
+		number int
+		want   string
+	}{
+		{1, "INV/2026/10/18/0001"},
+		{42, "INV/2026/10/18/0042"},
+		{9999, "INV/2026/10/18/9999"},
+		{10000, "INV/2026/10/18/10000"},
+	}
+
+	for _, tt := range tests {
+		if got := s.Format("INV/2026/10/18", tt.number); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.number, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+const (
+	PaymentMethodCash         = "cash"
+	PaymentMethodDebitCard    = "debit_card"
+	PaymentMethodEWallet      = "e_wallet"
+	PaymentMethodQRIS         = "qris"
+	PaymentMethodBankTransfer = "bank_transfer"
+)
+
+var PaymentMethods = []string{
+	PaymentMethodCash,
+	PaymentMethodDebitCard,
+	PaymentMethodEWallet,
+	PaymentMethodQRIS,
+	PaymentMethodBankTransfer,
+}
+
+type TransactionPayment struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Method        string `json:"method"`
+	Amount        int    `json:"amount"`
+}
+
+type CheckoutPayment struct {
+	Method string `json:"method"`
+	Amount int    `json:"amount"`
+}
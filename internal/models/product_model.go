@@ -1,8 +1,17 @@
 package models
 
+// CostPrice adalah harga pokok rata-rata tertimbang, diperbarui otomatis saat
+// penerimaan barang. MinStock 0 berarti product tidak dipantau low stock, ReorderQty > 0 membulatkan
+// saran order ke kelipatannya, SupplierID adalah supplier utama untuk reorder
 type Product struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Price int    `json:"price"`
-	Stock int    `json:"stok"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Price      int    `json:"price"`
+	CostPrice  int    `json:"cost_price"`
+	Stock      int    `json:"stok"`
+	CategoryID *int   `json:"category_id"`
+	TaxClass   string `json:"tax_class"`
+	MinStock   int    `json:"min_stock"`
+	ReorderQty int    `json:"reorder_qty"`
+	SupplierID *int   `json:"supplier_id"`
 }
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+const (
+	PromotionTypePrice    = "price"
+	PromotionTypeBuyXGetY = "buy_x_get_y"
+	PromotionTypeBundle   = "bundle"
+)
+
+// Promotion berlaku otomatis saat checkout.
+//   - price: harga satuan ProductID menjadi PromoPrice (mis. happy hour)
+//   - buy_x_get_y: tiap beli BuyQty ProductID, GetQty berikutnya gratis
+//   - bundle: satu set Items dijual seharga PromoPrice
+//
+// StartAt/EndAt, DaysOfWeek (0 = Minggu) dan StartTime/EndTime ("HH:MM")
+// membatasi kapan promo berlaku, kosong berarti tidak dibatasi.
+type Promotion struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	ProductID  *int            `json:"product_id,omitempty"`
+	BuyQty     int             `json:"buy_qty,omitempty"`
+	GetQty     int             `json:"get_qty,omitempty"`
+	PromoPrice int             `json:"promo_price,omitempty"`
+	Items      []PromotionItem `json:"items,omitempty"`
+	StartAt    *time.Time      `json:"start_at,omitempty"`
+	EndAt      *time.Time      `json:"end_at,omitempty"`
+	DaysOfWeek []int           `json:"days_of_week,omitempty"`
+	StartTime  string          `json:"start_time,omitempty"`
+	EndTime    string          `json:"end_time,omitempty"`
+	IsActive   bool            `json:"is_active"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type PromotionItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// IsActiveAt mengecek window tanggal, hari dan jam promo terhadap waktu t
+func (p *Promotion) IsActiveAt(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartAt != nil && t.Before(*p.StartAt) {
+		return false
+	}
+	if p.EndAt != nil && !t.Before(*p.EndAt) {
+		return false
+	}
+
+	if len(p.DaysOfWeek) > 0 {
+		match := false
+		for _, d := range p.DaysOfWeek {
+			if time.Weekday(d) == t.Weekday() {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	clock := t.Format("15:04")
+	if p.StartTime != "" && clock < p.StartTime {
+		return false
+	}
+	if p.EndTime != "" && clock >= p.EndTime {
+		return false
+	}
+
+	return true
+}
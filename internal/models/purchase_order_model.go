@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// alur status PO: draft -> sent -> partially_received -> received,
+// draft dan sent bisa cancelled
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note,omitempty"`
+	CreatedBy    string              `json:"created_by,omitempty"`
+	TotalCost    int                 `json:"total_cost"`
+	Lines        []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts     []GoodsReceipt      `json:"receipts,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type PurchaseOrderLine struct {
+	ID          int    `json:"id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitCost    int    `json:"unit_cost"`
+	ReceivedQty int    `json:"received_qty"`
+}
+
+type PurchaseOrderFilter struct {
+	SupplierID int
+	Status     string
+}
+
+// PurchaseOrderRequest dipakai untuk membuat dan mengubah PO draft
+type PurchaseOrderRequest struct {
+	SupplierID int                        `json:"supplier_id"`
+	Note       string                     `json:"note,omitempty"`
+	CreatedBy  string                     `json:"created_by,omitempty"`
+	Lines      []PurchaseOrderLineRequest `json:"lines"`
+}
+
+type PurchaseOrderLineRequest struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+	UnitCost  int `json:"unit_cost"`
+}
+
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	ReceivedBy      string             `json:"received_by"`
+	Note            string             `json:"note,omitempty"`
+	Lines           []GoodsReceiptLine `json:"lines"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type GoodsReceiptLine struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+	UnitCost  int `json:"unit_cost"`
+}
+
+// GoodsReceiptRequest, unit_cost kosong berarti memakai unit_cost di PO
+type GoodsReceiptRequest struct {
+	ReceivedBy string                    `json:"received_by"`
+	Note       string                    `json:"note,omitempty"`
+	Lines      []GoodsReceiptLineRequest `json:"lines"`
+}
+
+type GoodsReceiptLineRequest struct {
+	ProductID int  `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+	UnitCost  *int `json:"unit_cost,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// CheckoutQuote adalah hasil simulasi checkout tanpa menyimpan transaksi.
+// Valid false berarti checkout dengan keranjang yang sama akan ditolak.
+type CheckoutQuote struct {
+	Valid                 bool                `json:"valid"`
+	Lines                 []QuoteLine         `json:"lines"`
+	Failures              []CheckoutItemError `json:"failures"`
+	VoucherError          string              `json:"voucher_error,omitempty"`
+	GrossAmount           int                 `json:"gross_amount"`
+	DiscountAmount        int                 `json:"discount_amount"`
+	CartDiscountAmount    int                 `json:"cart_discount_amount"`
+	VoucherDiscountAmount int                 `json:"voucher_discount_amount"`
+	ServiceChargeAmount   int                 `json:"service_charge_amount"`
+	TaxableAmount         int                 `json:"taxable_amount"`
+	TaxAmount             int                 `json:"tax_amount"`
+	TotalAmount           int                 `json:"total_amount"`
+}
+
+type QuoteLine struct {
+	ProductID           int    `json:"product_id"`
+	ProductName         string `json:"product_name"`
+	Quantity            int    `json:"quantity"`
+	UnitPrice           int    `json:"unit_price"`
+	PromotionID         *int   `json:"promotion_id,omitempty"`
+	GrossAmount         int    `json:"gross_amount"`
+	DiscountAmount      int    `json:"discount_amount"`
+	Subtotal            int    `json:"subtotal"`
+	TaxClass            string `json:"tax_class"`
+	ServiceChargeAmount int    `json:"service_charge_amount"`
+	TaxAmount           int    `json:"tax_amount"`
+}
@@ -0,0 +1,16 @@
+package models
+
+const (
+	ReceiptFormatText   = "text"
+	ReceiptFormatESCPOS = "escpos"
+	ReceiptFormatPDF    = "pdf"
+)
+
+// StoreInfo dicetak di header dan footer struk
+type StoreInfo struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxID   string
+	Footer  string
+}
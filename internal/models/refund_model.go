@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type Refund struct {
+	ID            int            `json:"id"`
+	TransactionID int            `json:"transaction_id"`
+	TotalAmount   int            `json:"total_amount"`
+	Reason        string         `json:"reason"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Details       []RefundDetail `json:"details"`
+}
+
+type RefundDetail struct {
+	ID                  int `json:"id"`
+	RefundID            int `json:"refund_id"`
+	TransactionDetailID int `json:"transaction_detail_id"`
+	ProductID           int `json:"product_id"`
+	Quantity            int `json:"quantity"`
+	Amount              int `json:"amount"`
+}
+
+type RefundItem struct {
+	TransactionDetailID int `json:"transaction_detail_id"`
+	Quantity            int `json:"quantity"`
+}
+
+type RefundRequest struct {
+	Items  []RefundItem `json:"items"`
+	Reason string       `json:"reason"`
+}
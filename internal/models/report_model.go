@@ -1,12 +1,56 @@
 package models
 
+import "time"
+
 type BestSeller struct {
 	Nama       string `json:"nama"`
 	QtyTerjual int    `json:"qty_terjual"`
 }
 
+type PaymentMethodSummary struct {
+	Method         string `json:"method"`
+	TotalAmount    int    `json:"total_amount"`
+	TotalTransaksi int    `json:"total_transaksi"`
+}
+
 type SalesReport struct {
-	TotalRevenue   int        `json:"total_revenue"`
-	TotalTransaksi int        `json:"total_transaksi"`
-	ProdukTerlaris BestSeller `json:"produk_terlaris"`
+	GrossRevenue       int                    `json:"gross_revenue"`
+	TotalDiscount      int                    `json:"total_discount"`
+	TotalRevenue       int                    `json:"total_revenue"`
+	TotalRefund        int                    `json:"total_refund"`
+	TotalServiceCharge int                    `json:"total_service_charge"`
+	TotalTax           int                    `json:"total_tax"`
+	TotalTransaksi     int                    `json:"total_transaksi"`
+	ProdukTerlaris     BestSeller             `json:"produk_terlaris"`
+	Pembayaran         []PaymentMethodSummary `json:"pembayaran"`
+}
+
+const (
+	ProfitGroupByDay      = "day"
+	ProfitGroupByProduct  = "product"
+	ProfitGroupByCategory = "category"
+)
+
+// ProfitRow laba kotor satu kelompok (hari, product atau category). Revenue adalah
+// penjualan nett setelah diskon tanpa pajak dan service charge, COGS dari unit_cost
+// yang di-snapshot saat checkout. Qty yang di-refund tidak dihitung.
+type ProfitRow struct {
+	Key         string  `json:"key"`
+	Label       string  `json:"label"`
+	Quantity    int     `json:"quantity"`
+	Revenue     int     `json:"revenue"`
+	COGS        int     `json:"cogs"`
+	GrossProfit int     `json:"gross_profit"`
+	GrossMargin float64 `json:"gross_margin"`
+}
+
+type ProfitReport struct {
+	StartDate   time.Time   `json:"start_date"`
+	EndDate     time.Time   `json:"end_date"`
+	GroupBy     string      `json:"group_by"`
+	Revenue     int         `json:"revenue"`
+	COGS        int         `json:"cogs"`
+	GrossProfit int         `json:"gross_profit"`
+	GrossMargin float64     `json:"gross_margin"`
+	Rows        []ProfitRow `json:"rows"`
 }
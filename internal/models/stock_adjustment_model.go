@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// alasan penyesuaian stok: damaged, lost dan expired mengurangi stok, found menambah stok
+const (
+	AdjustmentReasonDamaged = "damaged"
+	AdjustmentReasonLost    = "lost"
+	AdjustmentReasonFound   = "found"
+	AdjustmentReasonExpired = "expired"
+)
+
+type StockAdjustment struct {
+	ID        int       `json:"id"`
+	ProductID int       `json:"product_id"`
+	Delta     int       `json:"delta"`
+	Reason    string    `json:"reason"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Balance   int       `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StockAdjustmentRequest, delta bertanda (negatif = stok berkurang)
+type StockAdjustmentRequest struct {
+	Delta     int    `json:"delta"`
+	Reason    string `json:"reason"`
+	Note      string `json:"note,omitempty"`
+	CreatedBy string `json:"created_by,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+const (
+	StockCountStatusOpen      = "open"
+	StockCountStatusPosted    = "posted"
+	StockCountStatusCancelled = "cancelled"
+)
+
+// StockCount adalah sesi stock opname. SystemStock tiap item adalah snapshot stok
+// saat sesi dibuka, selisih (counted - system) diterapkan relatif ke stok saat posting
+// sehingga penjualan selama penghitungan tidak hilang.
+type StockCount struct {
+	ID        int                `json:"id"`
+	Status    string             `json:"status"`
+	Note      string             `json:"note,omitempty"`
+	CreatedBy string             `json:"created_by"`
+	PostedBy  string             `json:"posted_by,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	ClosedAt  *time.Time         `json:"closed_at,omitempty"`
+	Summary   *StockCountSummary `json:"summary,omitempty"`
+	Items     []StockCountItem   `json:"items,omitempty"`
+}
+
+type StockCountItem struct {
+	ProductID   int        `json:"product_id"`
+	ProductName string     `json:"product_name"`
+	SystemStock int        `json:"system_stock"`
+	CountedQty  *int       `json:"counted_qty"`
+	Variance    *int       `json:"variance"`
+	CountedBy   string     `json:"counted_by,omitempty"`
+	CountedAt   *time.Time `json:"counted_at,omitempty"`
+}
+
+// StockCountSummary ringkasan hasil hitung untuk review sebelum posting
+type StockCountSummary struct {
+	TotalItems    int `json:"total_items"`
+	CountedItems  int `json:"counted_items"`
+	VarianceItems int `json:"variance_items"`
+	TotalVariance int `json:"total_variance"`
+}
+
+type OpenStockCountRequest struct {
+	Note      string `json:"note,omitempty"`
+	CreatedBy string `json:"created_by"`
+}
+
+// StockCountSubmission hasil hitung (boleh sebagian) dari satu petugas,
+// hitungan terakhir untuk product yang sama menggantikan yang sebelumnya
+type StockCountSubmission struct {
+	CountedBy string            `json:"counted_by"`
+	Items     []StockCountEntry `json:"items"`
+}
+
+type StockCountEntry struct {
+	ProductID  int `json:"product_id"`
+	CountedQty int `json:"counted_qty"`
+}
+
+type PostStockCountRequest struct {
+	PostedBy string `json:"posted_by"`
+}
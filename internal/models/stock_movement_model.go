@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// alasan perubahan stok
+const (
+	StockReasonInitial    = "initial"
+	StockReasonSale       = "sale"
+	StockReasonRefund     = "refund"
+	StockReasonVoid       = "void"
+	StockReasonAdjustment = "adjustment"
+	StockReasonReceipt    = "receipt"
+	StockReasonOpname     = "opname"
+)
+
+// dokumen sumber perubahan stok (reference_type)
+const (
+	StockReferenceProduct     = "product"
+	StockReferenceTransaction = "transaction"
+	StockReferenceRefund      = "refund"
+	StockReferenceAdjustment  = "stock_adjustment"
+	StockReferenceStockCount  = "stock_count"
+	StockReferenceReceipt     = "goods_receipt"
+)
+
+// StockMovement adalah satu baris kartu stok, Balance adalah stok setelah perubahan
+type StockMovement struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	Delta         int       `json:"delta"`
+	Balance       int       `json:"balance"`
+	Reason        string    `json:"reason"`
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   *int      `json:"reference_id,omitempty"`
+	CreatedBy     string    `json:"created_by,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type StockMovementFilter struct {
+	ProductID int
+	StartDate *time.Time
+	EndDate   *time.Time
+	Reason    string
+	Page      int
+	Limit     int
+}
+
+type StockMovementList struct {
+	Data  []StockMovement `json:"data"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+	Total int             `json:"total"`
+}
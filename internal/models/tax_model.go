@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	TaxClassTaxable = "taxable"
+	TaxClassExempt  = "exempt"
+)
+
+// TaxSettings diambil dari konfigurasi (TAX_RATE, TAX_INCLUSIVE, SERVICE_CHARGE_RATE).
+// Rate dalam persen, mis. 11 untuk PPN 11%.
+type TaxSettings struct {
+	Rate              float64
+	Inclusive         bool
+	ServiceChargeRate float64
+}
+
+type TaxSummary struct {
+	StartDate          time.Time `json:"start_date"`
+	EndDate            time.Time `json:"end_date"`
+	TotalTransaksi     int       `json:"total_transaksi"`
+	TaxableAmount      int       `json:"taxable_amount"`
+	ExemptAmount       int       `json:"exempt_amount"`
+	ServiceChargeTotal int       `json:"service_charge_total"`
+	TaxAmount          int       `json:"tax_amount"`
+	TaxRefunded        int       `json:"tax_refunded"`
+	NetTaxAmount       int       `json:"net_tax_amount"`
+}
@@ -2,27 +2,93 @@ package models
 
 import "time"
 
+const (
+	TransactionStatusCompleted = "completed"
+	TransactionStatusVoided    = "voided"
+)
+
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID                    int                  `json:"id"`
+	InvoiceNumber         string               `json:"invoice_number"`
+	GrossAmount           int                  `json:"gross_amount"`
+	DiscountAmount        int                  `json:"discount_amount"`
+	CartDiscountAmount    int                  `json:"cart_discount_amount"`
+	VoucherID             *int                 `json:"voucher_id,omitempty"`
+	VoucherDiscountAmount int                  `json:"voucher_discount_amount"`
+	CustomerRef           string               `json:"customer_ref,omitempty"`
+	ServiceChargeRate     float64              `json:"service_charge_rate"`
+	ServiceChargeAmount   int                  `json:"service_charge_amount"`
+	TaxRate               float64              `json:"tax_rate"`
+	TaxInclusive          bool                 `json:"tax_inclusive"`
+	TaxableAmount         int                  `json:"taxable_amount"`
+	TaxAmount             int                  `json:"tax_amount"`
+	TotalAmount           int                  `json:"total_amount"`
+	PaidAmount            int                  `json:"paid_amount"`
+	ChangeAmount          int                  `json:"change_amount"`
+	Status                string               `json:"status"`
+	VoidedBy              *string              `json:"voided_by,omitempty"`
+	VoidReason            *string              `json:"void_reason,omitempty"`
+	VoidedAt              *time.Time           `json:"voided_at,omitempty"`
+	CreatedAt             time.Time            `json:"created_at"`
+	Details               []TransactionDetail  `json:"details"`
+	Payments              []TransactionPayment `json:"payments"`
 }
 
+// ProductName dan UnitPrice adalah snapshot saat checkout, tidak mengikuti perubahan product
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID                  int    `json:"id"`
+	TransactionID       int    `json:"transaction_id"`
+	ProductID           int    `json:"product_id"`
+	ProductName         string `json:"product_name,omitempty"`
+	Quantity            int    `json:"quantity"`
+	UnitPrice           int    `json:"unit_price"`
+	UnitCost            int    `json:"-"` // harga pokok, hanya disimpan untuk laporan laba
+	PromotionID         *int   `json:"promotion_id,omitempty"`
+	GrossAmount         int    `json:"gross_amount"`
+	DiscountAmount      int    `json:"discount_amount"`
+	Subtotal            int    `json:"subtotal"`
+	TaxClass            string `json:"tax_class"`
+	ServiceChargeAmount int    `json:"service_charge_amount"`
+	TaxAmount           int    `json:"tax_amount"`
 }
 
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int       `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	Discount  *Discount `json:"discount,omitempty"`
 }
 
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items       []CheckoutItem    `json:"items"`
+	Discount    *Discount         `json:"discount,omitempty"`
+	VoucherCode string            `json:"voucher_code,omitempty"`
+	CustomerRef string            `json:"customer_ref,omitempty"`
+	Payments    []CheckoutPayment `json:"payments"`
+
+	// diisi saat checkout berasal dari held cart, tidak diterima dari body request
+	HeldCartID int `json:"-"`
+}
+
+type TransactionFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	MinAmount int
+	MaxAmount int
+	ProductID int
+	Status    string
+	Invoice   string
+	Page      int
+	Limit     int
+}
+
+type TransactionList struct {
+	Data  []Transaction `json:"data"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+	Total int           `json:"total"`
+}
+
+type VoidRequest struct {
+	VoidedBy string `json:"voided_by"`
+	Reason   string `json:"reason"`
 }
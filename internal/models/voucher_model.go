@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Voucher memakai tipe diskon yang sama dengan Discount (percentage / fixed).
+// Nilai 0 pada MaxDiscount, UsageLimit dan PerCustomerLimit berarti tanpa batas.
+type Voucher struct {
+	ID               int        `json:"id"`
+	Code             string     `json:"code"`
+	Type             string     `json:"type"`
+	Value            int        `json:"value"`
+	MaxDiscount      int        `json:"max_discount"`
+	MinPurchase      int        `json:"min_purchase"`
+	UsageLimit       int        `json:"usage_limit"`
+	PerCustomerLimit int        `json:"per_customer_limit"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	IsActive         bool       `json:"is_active"`
+	UsedCount        int        `json:"used_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// DiscountFor menghitung potongan voucher untuk nominal belanja base
+func (v *Voucher) DiscountFor(base int) int {
+	discount := (&Discount{Type: v.Type, Value: v.Value}).Amount(base)
+	if v.MaxDiscount > 0 && discount > v.MaxDiscount {
+		return v.MaxDiscount
+	}
+	return discount
+}
+
+type VoucherRedemptionReport struct {
+	VoucherID       int    `json:"voucher_id"`
+	Code            string `json:"code"`
+	TotalRedemption int    `json:"total_redemption"`
+	TotalDiscount   int    `json:"total_discount"`
+}
@@ -2,9 +2,15 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"kasir-api/internal/models"
 )
 
+var (
+	ErrCategoryInUse           = errors.New("category still has products")
+	ErrInvalidReassignCategory = errors.New("invalid target category for reassignment")
+)
+
 type CategoryRepository struct {
 	db *sql.DB
 }
@@ -108,8 +114,55 @@ func (r *CategoryRepository) Update(id int, updated models.Category) (models.Cat
 }
 
 // ===== DELETE =====
-func (r *CategoryRepository) Delete(id int) error {
-	result, err := r.db.Exec(`
+// Delete menolak menghapus category yang masih dipakai product.
+// Jika reassignTo > 0, product dipindahkan dulu ke category tersebut.
+func (r *CategoryRepository) Delete(id int, reassignTo int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if reassignTo > 0 {
+		if reassignTo == id {
+			return ErrInvalidReassignCategory
+		}
+
+		var exists bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)
+		`, reassignTo).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrInvalidReassignCategory
+		}
+
+		_, err = tx.Exec(`
+			UPDATE products
+			SET category_id = $1
+			WHERE category_id = $2
+		`, reassignTo, id)
+		if err != nil {
+			return err
+		}
+	} else {
+		var productCount int
+		err = tx.QueryRow(`
+			SELECT COUNT(*)
+			FROM products
+			WHERE category_id = $1
+		`, id).Scan(&productCount)
+		if err != nil {
+			return err
+		}
+		if productCount > 0 {
+			return ErrCategoryInUse
+		}
+	}
+
+	result, err := tx.Exec(`
 		DELETE FROM categories
 		WHERE id = $1
 	`, id)
@@ -126,5 +179,5 @@ func (r *CategoryRepository) Delete(id int) error {
 		return sql.ErrNoRows
 	}
 
-	return nil
+	return tx.Commit()
 }
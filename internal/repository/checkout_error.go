@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"strings"
+)
+
+var (
+	ErrEmptyCart         = errors.New("cart has no items")
+	ErrInvalidQuantity   = errors.New("quantity must be greater than 0")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// CheckoutError berisi semua baris keranjang yang gagal, bukan hanya yang pertama.
+// errors.Is(err, ErrInsufficientStock) dst. bernilai true jika ada baris dengan reason tersebut.
+type CheckoutError struct {
+	Items []models.CheckoutItemError
+}
+
+func (e *CheckoutError) Error() string {
+	msgs := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		switch item.Reason {
+		case models.CheckoutErrorEmptyCart:
+			msgs[i] = ErrEmptyCart.Error()
+		case models.CheckoutErrorInvalidQuantity:
+			msgs[i] = fmt.Sprintf("product id %d: %s", item.ProductID, ErrInvalidQuantity)
+		case models.CheckoutErrorInvalidProduct:
+			msgs[i] = fmt.Sprintf("product id %d is invalid", item.ProductID)
+		case models.CheckoutErrorConflictingDiscount:
+			msgs[i] = fmt.Sprintf("product id %d appears on several lines with discounts that cannot be combined", item.ProductID)
+		case models.CheckoutErrorProductNotFound:
+			msgs[i] = fmt.Sprintf("product id %d not found", item.ProductID)
+		case models.CheckoutErrorInsufficientStock:
+			msgs[i] = fmt.Sprintf("stock product %s not enough (available %d, requested %d)", item.ProductName, item.Available, item.Quantity)
+		default:
+			msgs[i] = fmt.Sprintf("product id %d: %s", item.ProductID, item.Reason)
+		}
+	}
+	return "checkout failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *CheckoutError) Is(target error) bool {
+	reason := ""
+	switch target {
+	case ErrEmptyCart:
+		reason = models.CheckoutErrorEmptyCart
+	case ErrInvalidQuantity:
+		reason = models.CheckoutErrorInvalidQuantity
+	case ErrProductNotFound:
+		reason = models.CheckoutErrorProductNotFound
+	case ErrInsufficientStock:
+		reason = models.CheckoutErrorInsufficientStock
+	default:
+		return false
+	}
+
+	return e.HasReason(reason)
+}
+
+func (e *CheckoutError) HasReason(reason string) bool {
+	for _, item := range e.Items {
+		if item.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
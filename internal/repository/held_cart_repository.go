@@ -0,0 +1,545 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrHeldCartNotActive        = errors.New("held cart already resumed or expired")
+	ErrHeldCartLabelExists      = errors.New("cashier already has a held cart with this label")
+	ErrHeldCartProductNotFound  = errors.New("product not found")
+	ErrHeldCartStockUnavailable = errors.New("stock not available to reserve")
+)
+
+type HeldCartRepository struct {
+	db *sql.DB
+}
+
+func NewHeldCartRepository(db *sql.DB) *HeldCartRepository {
+	return &HeldCartRepository{
+		db: db,
+	}
+}
+
+// heldCartStatus adalah status held cart saat dibaca: keranjang held yang sudah lewat
+// expires_at dibaca sebagai expired tanpa harus menunggu expireStale di jalur tulis
+const heldCartStatus = `CASE WHEN status = '` + models.HeldCartStatusHeld + `' AND expires_at <= NOW()
+	THEN '` + models.HeldCartStatusExpired + `' ELSE status END`
+
+const heldCartColumns = `id, cashier, label, discount_type, discount_value, voucher_code, customer_ref,
+	reserve_stock, ` + heldCartStatus + `, transaction_id, expires_at, created_at, updated_at`
+
+func scanHeldCart(row rowScanner) (models.HeldCart, error) {
+	var c models.HeldCart
+	var discountType sql.NullString
+	var discountValue sql.NullInt64
+
+	err := row.Scan(
+		&c.ID,
+		&c.Cashier,
+		&c.Label,
+		&discountType,
+		&discountValue,
+		&c.VoucherCode,
+		&c.CustomerRef,
+		&c.ReserveStock,
+		&c.Status,
+		&c.TransactionID,
+		&c.ExpiresAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	c.Discount = nullDiscount(discountType, discountValue)
+	return c, nil
+}
+
+// nullDiscount / discountColumns memetakan *Discount ke kolom discount_type dan discount_value
+func nullDiscount(discountType sql.NullString, discountValue sql.NullInt64) *models.Discount {
+	if !discountType.Valid {
+		return nil
+	}
+	return &models.Discount{Type: discountType.String, Value: int(discountValue.Int64)}
+}
+
+func discountColumns(d *models.Discount) (interface{}, interface{}) {
+	if d == nil {
+		return nil, nil
+	}
+	return d.Type, d.Value
+}
+
+// ===== GET ALL =====
+// cashier dan status kosong berarti tanpa filter
+func (r *HeldCartRepository) GetAll(cashier, status string) ([]models.HeldCart, error) {
+	var conditions []string
+	var args []interface{}
+
+	if cashier != "" {
+		args = append(args, cashier)
+		conditions = append(conditions, fmt.Sprintf("cashier = $%d", len(args)))
+	}
+
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("(%s) = $%d", heldCartStatus, len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(`SELECT `+heldCartColumns+` FROM held_carts`+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carts := make([]models.HeldCart, 0)
+	var ids []int
+
+	for rows.Next() {
+		c, err := scanHeldCart(rows)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, c)
+		ids = append(ids, c.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range carts {
+		carts[i].Items = items[carts[i].ID]
+	}
+
+	return carts, nil
+}
+
+// ===== GET BY ID =====
+func (r *HeldCartRepository) GetByID(id int) (models.HeldCart, error) {
+	c, err := scanHeldCart(r.db.QueryRow(`
+		SELECT `+heldCartColumns+`
+		FROM held_carts
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	items, err := r.getItems([]int{c.ID})
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+	c.Items = items[c.ID]
+
+	return c, nil
+}
+
+// ===== CREATE =====
+// Create menyimpan held cart yang berlaku selama ttl
+func (r *HeldCartRepository) Create(cart models.HeldCart, ttl time.Duration) (models.HeldCart, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+	defer tx.Rollback()
+
+	if err := r.expireStale(tx); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := checkHeldCartItems(tx, 0, cart.Items, cart.ReserveStock); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	discountType, discountValue := discountColumns(cart.Discount)
+	err = tx.QueryRow(`
+		INSERT INTO held_carts (cashier, label, discount_type, discount_value, voucher_code, customer_ref,
+			reserve_stock, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW() + $8 * INTERVAL '1 second')
+		RETURNING id, status, created_at, updated_at
+	`,
+		cart.Cashier,
+		cart.Label,
+		discountType,
+		discountValue,
+		cart.VoucherCode,
+		cart.CustomerRef,
+		cart.ReserveStock,
+		ttl.Seconds(),
+	).Scan(&cart.ID, &cart.Status, &cart.CreatedAt, &cart.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.HeldCart{}, ErrHeldCartLabelExists
+	}
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := insertHeldCartItems(tx, cart.ID, cart.Items); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	return r.GetByID(cart.ID)
+}
+
+// ===== UPDATE =====
+// Update mengganti isi keranjang dan memperpanjang masa hold sebesar ttl, hanya untuk status held
+func (r *HeldCartRepository) Update(id int, cart models.HeldCart, ttl time.Duration) (models.HeldCart, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+	defer tx.Rollback()
+
+	if err := r.expireStale(tx); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := lockHeldCart(tx, id); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := checkHeldCartItems(tx, id, cart.Items, cart.ReserveStock); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	discountType, discountValue := discountColumns(cart.Discount)
+	_, err = tx.Exec(`
+		UPDATE held_carts
+		SET cashier = $1, label = $2, discount_type = $3, discount_value = $4, voucher_code = $5,
+			customer_ref = $6, reserve_stock = $7, expires_at = NOW() + $8 * INTERVAL '1 second', updated_at = NOW()
+		WHERE id = $9
+	`,
+		cart.Cashier,
+		cart.Label,
+		discountType,
+		discountValue,
+		cart.VoucherCode,
+		cart.CustomerRef,
+		cart.ReserveStock,
+		ttl.Seconds(),
+		id,
+	)
+	if isUniqueViolation(err) {
+		return models.HeldCart{}, ErrHeldCartLabelExists
+	}
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM held_cart_items WHERE held_cart_id = $1`, id); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := insertHeldCartItems(tx, id, cart.Items); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// ===== EXPIRE =====
+// Expire melepas held cart (dan reservasi stoknya) tanpa membuat transaksi
+func (r *HeldCartRepository) Expire(id int) (models.HeldCart, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+	defer tx.Rollback()
+
+	if err := r.expireStale(tx); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := lockHeldCart(tx, id); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE held_carts
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`, models.HeldCartStatusExpired, id)
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.HeldCart{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// expireStale menandai held cart yang sudah lewat expires_at sebagai expired,
+// hanya dipanggil di jalur tulis (jalur baca memakai heldCartStatus)
+func (r *HeldCartRepository) expireStale(q execer) error {
+	_, err := q.Exec(`
+		UPDATE held_carts
+		SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND expires_at <= NOW()
+	`, models.HeldCartStatusExpired, models.HeldCartStatusHeld)
+	return err
+}
+
+func (r *HeldCartRepository) getItems(cartIDs []int) (map[int][]models.HeldCartItem, error) {
+	result := make(map[int][]models.HeldCartItem)
+	if len(cartIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT i.held_cart_id, i.id, i.product_id, COALESCE(p.name, ''), i.quantity, i.discount_type, i.discount_value
+		FROM held_cart_items i
+		LEFT JOIN products p ON p.id = i.product_id
+		WHERE i.held_cart_id = ANY($1)
+		ORDER BY i.id
+	`, pq.Array(cartIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cartID int
+		var item models.HeldCartItem
+		var discountType sql.NullString
+		var discountValue sql.NullInt64
+
+		if err := rows.Scan(&cartID, &item.ID, &item.ProductID, &item.ProductName, &item.Quantity, &discountType, &discountValue); err != nil {
+			return nil, err
+		}
+
+		item.Discount = nullDiscount(discountType, discountValue)
+		result[cartID] = append(result[cartID], item)
+	}
+
+	return result, rows.Err()
+}
+
+// execer dan queryer dipenuhi *sql.DB dan *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// insertHeldCartItems menyimpan semua item dengan satu INSERT, urutan item dipertahankan
+func insertHeldCartItems(tx *sql.Tx, cartID int, items []models.HeldCartItem) error {
+	n := len(items)
+	productIDs := make([]int, n)
+	quantities := make([]int, n)
+	discountTypes := make([]sql.NullString, n)
+	discountValues := make([]sql.NullInt64, n)
+
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+		quantities[i] = item.Quantity
+		if item.Discount != nil {
+			discountTypes[i] = sql.NullString{String: item.Discount.Type, Valid: true}
+			discountValues[i] = sql.NullInt64{Int64: int64(item.Discount.Value), Valid: true}
+		}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO held_cart_items (held_cart_id, product_id, quantity, discount_type, discount_value)
+		SELECT $1, i.product_id, i.quantity, i.discount_type, i.discount_value
+		FROM unnest($2::int[], $3::int[], $4::varchar[], $5::int[])
+			WITH ORDINALITY AS i(product_id, quantity, discount_type, discount_value, ord)
+		ORDER BY i.ord
+	`,
+		cartID,
+		pq.Array(productIDs),
+		pq.Array(quantities),
+		pq.Array(discountTypes),
+		pq.Array(discountValues),
+	)
+	return err
+}
+
+// lockHeldCart mengunci held cart dan memastikan statusnya masih held dan belum lewat expires_at
+func lockHeldCart(tx *sql.Tx, id int) error {
+	var status string
+	var expired bool
+	err := tx.QueryRow(`
+		SELECT status, expires_at <= NOW()
+		FROM held_carts
+		WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&status, &expired)
+	if err != nil {
+		return err
+	}
+
+	if status != models.HeldCartStatusHeld || expired {
+		return ErrHeldCartNotActive
+	}
+	return nil
+}
+
+// checkHeldCartItems memastikan semua product ada. Jika reserve true, product dikunci
+// (urut ID supaya tidak deadlock) dan stok yang belum direservasi keranjang lain harus cukup.
+func checkHeldCartItems(tx *sql.Tx, cartID int, items []models.HeldCartItem, reserve bool) error {
+	qty := make(map[int]int)
+	cartItems := make([]models.CheckoutItem, 0, len(items))
+	for _, item := range items {
+		if _, ok := qty[item.ProductID]; !ok {
+			cartItems = append(cartItems, models.CheckoutItem{ProductID: item.ProductID})
+		}
+		qty[item.ProductID] += item.Quantity
+	}
+	sort.Slice(cartItems, func(i, j int) bool {
+		return cartItems[i].ProductID < cartItems[j].ProductID
+	})
+
+	products, err := loadCartProducts(tx, cartItems, cartID, reserve)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range cartItems {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return fmt.Errorf("%w: id %d", ErrHeldCartProductNotFound, item.ProductID)
+		}
+
+		if !reserve {
+			continue
+		}
+
+		if available := product.stock - product.reserved; available < qty[item.ProductID] {
+			return fmt.Errorf("%w: product %s, available %d", ErrHeldCartStockUnavailable, product.name, available)
+		}
+	}
+
+	return nil
+}
+
+// reservedStocks menjumlahkan qty per product yang direservasi held cart aktif, kecuali
+// held cart excludeCartID (keranjang yang sedang di-checkout / diubah). Product tanpa
+// reservasi tidak ada di map.
+func reservedStocks(q queryer, productIDs []int, excludeCartID int) (map[int]int, error) {
+	rows, err := q.Query(`
+		SELECT i.product_id, SUM(i.quantity)
+		FROM held_cart_items i
+		JOIN held_carts c ON c.id = i.held_cart_id
+		WHERE i.product_id = ANY($1)
+			AND c.id <> $2
+			AND c.reserve_stock
+			AND c.status = $3
+			AND c.expires_at > NOW()
+		GROUP BY i.product_id
+	`, pq.Array(productIDs), excludeCartID, models.HeldCartStatusHeld)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reserved := make(map[int]int)
+	for rows.Next() {
+		var id, qty int
+		if err := rows.Scan(&id, &qty); err != nil {
+			return nil, err
+		}
+		reserved[id] = qty
+	}
+
+	return reserved, rows.Err()
+}
+
+// reservedStockQuery adalah subquery qty product p yang direservasi held cart aktif,
+// parameter $2 = held cart yang dikecualikan, $3 = status held
+const reservedStockQuery = `
+	SELECT COALESCE(SUM(i.quantity), 0)
+	FROM held_cart_items i
+	JOIN held_carts c ON c.id = i.held_cart_id
+	WHERE i.product_id = p.id
+		AND c.id <> $2
+		AND c.reserve_stock
+		AND c.status = $3
+		AND c.expires_at > NOW()`
+
+// loadHeldCartCheckout mengisi item, diskon, voucher dan customer req dari held cart
+// req.HeldCartID, dipanggil di dalam tx checkout setelah lockHeldCart
+func loadHeldCartCheckout(tx *sql.Tx, req models.CheckoutRequest) (models.CheckoutRequest, error) {
+	var discountType sql.NullString
+	var discountValue sql.NullInt64
+
+	err := tx.QueryRow(`
+		SELECT discount_type, discount_value, voucher_code, customer_ref
+		FROM held_carts
+		WHERE id = $1
+	`, req.HeldCartID).Scan(&discountType, &discountValue, &req.VoucherCode, &req.CustomerRef)
+	if err != nil {
+		return req, err
+	}
+	req.Discount = nullDiscount(discountType, discountValue)
+
+	rows, err := tx.Query(`
+		SELECT product_id, quantity, discount_type, discount_value
+		FROM held_cart_items
+		WHERE held_cart_id = $1
+		ORDER BY id
+	`, req.HeldCartID)
+	if err != nil {
+		return req, err
+	}
+	defer rows.Close()
+
+	req.Items = nil
+	for rows.Next() {
+		var item models.CheckoutItem
+		var itemDiscountType sql.NullString
+		var itemDiscountValue sql.NullInt64
+
+		if err := rows.Scan(&item.ProductID, &item.Quantity, &itemDiscountType, &itemDiscountValue); err != nil {
+			return req, err
+		}
+
+		item.Discount = nullDiscount(itemDiscountType, itemDiscountValue)
+		req.Items = append(req.Items, item)
+	}
+
+	return req, rows.Err()
+}
+
+// markHeldCartResumed menghubungkan held cart dengan transaksi hasil checkout,
+// dipanggil di dalam tx checkout setelah lockHeldCart
+func markHeldCartResumed(tx *sql.Tx, id, transactionID int) error {
+	_, err := tx.Exec(`
+		UPDATE held_carts
+		SET status = $1, transaction_id = $2, updated_at = NOW()
+		WHERE id = $3
+	`, models.HeldCartStatusResumed, transactionID, id)
+	return err
+}
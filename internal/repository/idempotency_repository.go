@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/models"
+	"time"
+)
+
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve mencoba mengklaim key untuk request baru. Jika key sudah dipakai
+// (dan belum kedaluwarsa) record yang ada dikembalikan dengan reserved = false.
+// Key yang dibuat sebelum expiredBefore dianggap kedaluwarsa, dan key yang belum
+// selesai diproses dengan heartbeat terakhir sebelum abandonedBefore dianggap
+// ditinggalkan; keduanya boleh dipakai ulang. Key kedaluwarsa lain dibersihkan DeleteExpired.
+func (repo *IdempotencyRepository) Reserve(key, requestHash string, expiredBefore, abandonedBefore time.Time) (reserved bool, existing *models.IdempotencyRecord, err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM idempotency_keys
+		WHERE key = $1
+			AND (created_at < $2 OR (status_code IS NULL AND heartbeat_at < $3))
+	`, key, expiredBefore, abandonedBefore)
+	if err != nil {
+		return false, nil, err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO idempotency_keys (key, request_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO NOTHING
+	`, key, requestHash)
+	if err != nil {
+		return false, nil, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, nil, err
+	}
+
+	if inserted == 0 {
+		var record models.IdempotencyRecord
+		var contentType sql.NullString
+		err = tx.QueryRow(`
+			SELECT key, request_hash, status_code, content_type, response, created_at
+			FROM idempotency_keys
+			WHERE key = $1
+		`, key).Scan(
+			&record.Key,
+			&record.RequestHash,
+			&record.StatusCode,
+			&contentType,
+			&record.Response,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return false, nil, err
+		}
+		record.ContentType = contentType.String
+		existing = &record
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, nil, err
+	}
+
+	return inserted > 0, existing, nil
+}
+
+// DeleteExpired menghapus paling banyak limit key yang dibuat sebelum expiredBefore
+// (lewat index created_at) dan mengembalikan jumlah yang terhapus
+func (repo *IdempotencyRepository) DeleteExpired(expiredBefore time.Time, limit int) (int64, error) {
+	result, err := repo.db.Exec(`
+		DELETE FROM idempotency_keys
+		WHERE key IN (
+			SELECT key
+			FROM idempotency_keys
+			WHERE created_at < $1
+			ORDER BY created_at
+			LIMIT $2
+		)
+	`, expiredBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Touch memperbarui heartbeat key yang masih diproses
+func (repo *IdempotencyRepository) Touch(key string) error {
+	_, err := repo.db.Exec(`
+		UPDATE idempotency_keys
+		SET heartbeat_at = NOW()
+		WHERE key = $1 AND status_code IS NULL
+	`, key)
+	return err
+}
+
+// Complete menyimpan response dari request yang sudah selesai diproses
+func (repo *IdempotencyRepository) Complete(key string, statusCode int, contentType string, response []byte) error {
+	_, err := repo.db.Exec(`
+		UPDATE idempotency_keys
+		SET status_code = $2, content_type = $3, response = $4
+		WHERE key = $1
+	`, key, statusCode, contentType, response)
+	return err
+}
+
+// Release menghapus key supaya request bisa dicoba ulang
+func (repo *IdempotencyRepository) Release(key string) error {
+	_, err := repo.db.Exec(`
+		DELETE FROM idempotency_keys
+		WHERE key = $1
+	`, key)
+	return err
+}
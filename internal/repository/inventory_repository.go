@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type InventoryRepository struct {
+	db *sql.DB
+}
+
+func NewInventoryRepository(db *sql.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: db,
+	}
+}
+
+// inventoryQuery adalah CTE inv berisi posisi stok tiap product: reservasi held cart,
+// qty yang masih dipesan di PO sent / partially_received, dan supplier (supplier utama
+// product, atau supplier PO terakhir). Parameter $1 = status PO yang masih terbuka,
+// $2 dan $3 mengikuti reservedStockQuery.
+const inventoryQuery = `
+	WITH inv AS (
+		SELECT
+			p.id,
+			p.name,
+			p.stock,
+			(` + reservedStockQuery + `) AS reserved,
+			p.min_stock,
+			p.reorder_qty,
+			(
+				SELECT COALESCE(SUM(l.quantity - l.received_qty), 0)
+				FROM purchase_order_lines l
+				JOIN purchase_orders po ON po.id = l.purchase_order_id
+				WHERE l.product_id = p.id
+					AND po.status = ANY($1)
+			) AS on_order,
+			COALESCE(p.supplier_id, (
+				SELECT po.supplier_id
+				FROM purchase_order_lines l
+				JOIN purchase_orders po ON po.id = l.purchase_order_id
+				WHERE l.product_id = p.id
+				ORDER BY po.id DESC
+				LIMIT 1
+			)) AS supplier_id
+		FROM products p
+	)`
+
+func inventoryArgs(extra ...interface{}) []interface{} {
+	args := []interface{}{
+		pq.Array([]string{models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived}),
+		0,
+		models.HeldCartStatusHeld,
+	}
+	return append(args, extra...)
+}
+
+// GetLowStock mengambil product dengan min_stock > 0 yang stok tersedianya
+// (stok dikurangi reservasi) sudah di bawah atau sama dengan min_stock
+func (r *InventoryRepository) GetLowStock() ([]models.LowStockItem, error) {
+	rows, err := r.db.Query(inventoryQuery+`
+		SELECT inv.id, inv.name, inv.stock, inv.reserved, inv.min_stock, inv.reorder_qty, inv.on_order,
+			inv.supplier_id, COALESCE(s.name, '')
+		FROM inv
+		LEFT JOIN suppliers s ON s.id = inv.supplier_id
+		WHERE inv.min_stock > 0
+			AND inv.stock - inv.reserved <= inv.min_stock
+		ORDER BY (inv.stock - inv.reserved) - inv.min_stock, inv.id
+	`, inventoryArgs()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.LowStockItem, 0)
+	for rows.Next() {
+		var item models.LowStockItem
+		err := rows.Scan(
+			&item.ProductID,
+			&item.ProductName,
+			&item.Stock,
+			&item.Reserved,
+			&item.MinStock,
+			&item.ReorderQty,
+			&item.OnOrder,
+			&item.SupplierID,
+			&item.SupplierName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Available = item.Stock - item.Reserved
+		item.Shortage = item.MinStock - item.Available
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetReorderCandidates mengambil posisi stok semua product beserta qty terjual
+// (dikurangi refund, transaksi void tidak dihitung) sejak since dan harga beli
+// terakhir (penerimaan barang terakhir, atau unit_cost PO terakhir)
+func (r *InventoryRepository) GetReorderCandidates(since time.Time) ([]models.ReorderCandidate, error) {
+	rows, err := r.db.Query(inventoryQuery+`
+		SELECT
+			inv.id,
+			inv.name,
+			inv.stock - inv.reserved,
+			inv.on_order,
+			inv.min_stock,
+			inv.reorder_qty,
+			(
+				SELECT COALESCE(SUM(td.quantity - COALESCE((
+					SELECT SUM(rd.quantity)
+					FROM refund_details rd
+					WHERE rd.transaction_detail_id = td.id
+				), 0)), 0)
+				FROM transaction_details td
+				JOIN transactions t ON t.id = td.transaction_id
+				WHERE td.product_id = inv.id
+					AND t.created_at >= $4
+					AND t.status <> $5
+			),
+			COALESCE(
+				(SELECT gl.unit_cost FROM goods_receipt_lines gl WHERE gl.product_id = inv.id ORDER BY gl.id DESC LIMIT 1),
+				(SELECT l.unit_cost FROM purchase_order_lines l WHERE l.product_id = inv.id ORDER BY l.id DESC LIMIT 1),
+				0
+			),
+			inv.supplier_id,
+			COALESCE(s.name, '')
+		FROM inv
+		LEFT JOIN suppliers s ON s.id = inv.supplier_id
+		ORDER BY inv.id
+	`, inventoryArgs(since, models.TransactionStatusVoided)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := make([]models.ReorderCandidate, 0)
+	for rows.Next() {
+		var c models.ReorderCandidate
+		err := rows.Scan(
+			&c.ProductID,
+			&c.ProductName,
+			&c.Available,
+			&c.OnOrder,
+			&c.MinStock,
+			&c.ReorderQty,
+			&c.QtySold,
+			&c.UnitCost,
+			&c.SupplierID,
+			&c.SupplierName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
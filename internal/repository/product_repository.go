@@ -2,9 +2,16 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"kasir-api/internal/models"
+	"strings"
 )
 
+// ErrProductInUse dikembalikan saat product yang sudah punya kartu stok (atau masih
+// direferensikan data lain) dihapus, histori stok tidak boleh ikut terhapus
+var ErrProductInUse = errors.New("product has stock history or is still referenced and cannot be deleted")
+
 type ProductRepository struct {
 	db *sql.DB
 }
@@ -15,16 +22,28 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	}
 }
 
-func (r *ProductRepository) GetAll(name string) ([]models.Product, error) {
-	query := "SELECT id, name, price, stock FROM products"
+func (r *ProductRepository) GetAll(name string, categoryID int) ([]models.Product, error) {
+	query := "SELECT id, name, price, cost_price, stock, category_id, tax_class, min_stock, reorder_qty, supplier_id FROM products"
 
+	var conditions []string
 	var args []interface{}
 
 	if name != "" {
-		query += " WHERE name ILIKE $1"
 		args = append(args, "%"+name+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+
+	if categoryID > 0 {
+		args = append(args, categoryID)
+		conditions = append(conditions, fmt.Sprintf("category_id = $%d", len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
+	query += " ORDER BY id"
+
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -39,7 +58,13 @@ func (r *ProductRepository) GetAll(name string) ([]models.Product, error) {
 			&p.ID,
 			&p.Name,
 			&p.Price,
+			&p.CostPrice,
 			&p.Stock,
+			&p.CategoryID,
+			&p.TaxClass,
+			&p.MinStock,
+			&p.ReorderQty,
+			&p.SupplierID,
 		); err != nil {
 			return nil, err
 		}
@@ -56,7 +81,7 @@ func (r *ProductRepository) GetAll(name string) ([]models.Product, error) {
 
 func (r *ProductRepository) GetByID(id int) (models.Product, error) {
 	query := `
-		SELECT id, name, price, stock
+		SELECT id, name, price, cost_price, stock, category_id, tax_class, min_stock, reorder_qty, supplier_id
 		FROM products
 		WHERE id = $1
 	`
@@ -66,7 +91,13 @@ func (r *ProductRepository) GetByID(id int) (models.Product, error) {
 		&p.ID,
 		&p.Name,
 		&p.Price,
+		&p.CostPrice,
 		&p.Stock,
+		&p.CategoryID,
+		&p.TaxClass,
+		&p.MinStock,
+		&p.ReorderQty,
+		&p.SupplierID,
 	)
 
 	if err != nil {
@@ -79,42 +110,71 @@ func (r *ProductRepository) GetByID(id int) (models.Product, error) {
 	return p, nil
 }
 
+// Create menyimpan product, stok awal dicatat di kartu stok sebagai initial
 func (r *ProductRepository) Create(product models.Product) (models.Product, error) {
-	query := `
-		INSERT INTO products (name, price, stock)
-		VALUES ($1, $2, $3)
-		RETURNING id
-	`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Product{}, err
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRow(
-		query,
+	err = tx.QueryRow(`
+		INSERT INTO products (name, price, cost_price, stock, category_id, tax_class, min_stock, reorder_qty, supplier_id)
+		VALUES ($1, $2, $3, 0, $4, $5, $6, $7, $8)
+		RETURNING id
+	`,
 		product.Name,
 		product.Price,
-		product.Stock,
+		product.CostPrice,
+		product.CategoryID,
+		product.TaxClass,
+		product.MinStock,
+		product.ReorderQty,
+		product.SupplierID,
 	).Scan(&product.ID)
 
 	if err != nil {
 		return models.Product{}, err
 	}
 
+	err = moveStock(tx, []int{product.ID}, []int{product.Stock}, models.StockMovement{
+		Reason:        models.StockReasonInitial,
+		ReferenceType: models.StockReferenceProduct,
+		ReferenceID:   &product.ID,
+	})
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Product{}, err
+	}
+
 	return product, nil
 }
 
+// Update mengubah data product kecuali stok, perubahan stok lewat stock adjustment
 func (r *ProductRepository) Update(id int, updated models.Product) (models.Product, error) {
 	query := `
 		UPDATE products
-		SET name = $1, price = $2, stock = $3
-		WHERE id = $4
-		RETURNING id
+		SET name = $1, price = $2, cost_price = $3, category_id = $4, tax_class = $5, min_stock = $6, reorder_qty = $7,
+			supplier_id = $8
+		WHERE id = $9
+		RETURNING id, stock
 	`
 
 	err := r.db.QueryRow(
 		query,
 		updated.Name,
 		updated.Price,
-		updated.Stock,
+		updated.CostPrice,
+		updated.CategoryID,
+		updated.TaxClass,
+		updated.MinStock,
+		updated.ReorderQty,
+		updated.SupplierID,
 		id,
-	).Scan(&updated.ID)
+	).Scan(&updated.ID, &updated.Stock)
 
 	if err != nil {
 		return models.Product{}, err
@@ -124,9 +184,23 @@ func (r *ProductRepository) Update(id int, updated models.Product) (models.Produ
 }
 
 func (r *ProductRepository) Delete(id int) error {
+	var inUse bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM stock_movements WHERE product_id = $1)
+	`, id).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrProductInUse
+	}
+
 	query := `DELETE FROM products WHERE id = $1`
 
 	result, err := r.db.Exec(query, id)
+	if isForeignKeyViolation(err) {
+		return ErrProductInUse
+	}
 	if err != nil {
 		return err
 	}
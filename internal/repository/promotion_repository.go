@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var ErrPromotionInUse = errors.New("promotion already used by transactions")
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+const promotionColumns = `id, name, type, product_id, buy_qty, get_qty, promo_price,
+	start_at, end_at, days_of_week, start_time, end_time, is_active, created_at`
+
+func scanPromotion(row rowScanner) (models.Promotion, error) {
+	var p models.Promotion
+	var days pq.Int64Array
+
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Type,
+		&p.ProductID,
+		&p.BuyQty,
+		&p.GetQty,
+		&p.PromoPrice,
+		&p.StartAt,
+		&p.EndAt,
+		&days,
+		&p.StartTime,
+		&p.EndTime,
+		&p.IsActive,
+		&p.CreatedAt,
+	)
+	if err != nil {
+		return models.Promotion{}, err
+	}
+
+	for _, d := range days {
+		p.DaysOfWeek = append(p.DaysOfWeek, int(d))
+	}
+
+	return p, nil
+}
+
+// ===== GET ALL =====
+func (r *PromotionRepository) GetAll() ([]models.Promotion, error) {
+	return r.query(`SELECT ` + promotionColumns + ` FROM promotions ORDER BY id`)
+}
+
+// ===== GET ACTIVE =====
+// GetActive mengambil promo aktif dalam rentang tanggal, filter hari dan jam
+// dilakukan oleh Promotion.IsActiveAt
+func (r *PromotionRepository) GetActive(now time.Time) ([]models.Promotion, error) {
+	return r.query(`
+		SELECT `+promotionColumns+`
+		FROM promotions
+		WHERE is_active
+			AND (start_at IS NULL OR start_at <= $1)
+			AND (end_at IS NULL OR end_at > $1)
+		ORDER BY id
+	`, now)
+}
+
+// ===== GET BY ID =====
+func (r *PromotionRepository) GetByID(id int) (models.Promotion, error) {
+	p, err := scanPromotion(r.db.QueryRow(`
+		SELECT `+promotionColumns+`
+		FROM promotions
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		return models.Promotion{}, err
+	}
+
+	items, err := r.getItems([]int{p.ID})
+	if err != nil {
+		return models.Promotion{}, err
+	}
+	p.Items = items[p.ID]
+
+	return p, nil
+}
+
+// ===== CREATE =====
+func (r *PromotionRepository) Create(promo models.Promotion) (models.Promotion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO promotions (name, type, product_id, buy_qty, get_qty, promo_price,
+			start_at, end_at, days_of_week, start_time, end_time, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at
+	`,
+		promo.Name,
+		promo.Type,
+		promo.ProductID,
+		promo.BuyQty,
+		promo.GetQty,
+		promo.PromoPrice,
+		promo.StartAt,
+		promo.EndAt,
+		pq.Array(promo.DaysOfWeek),
+		promo.StartTime,
+		promo.EndTime,
+		promo.IsActive,
+	).Scan(&promo.ID, &promo.CreatedAt)
+	if err != nil {
+		return models.Promotion{}, err
+	}
+
+	if err := insertPromotionItems(tx, promo.ID, promo.Items); err != nil {
+		return models.Promotion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Promotion{}, err
+	}
+
+	return promo, nil
+}
+
+// ===== UPDATE =====
+func (r *PromotionRepository) Update(id int, updated models.Promotion) (models.Promotion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE promotions
+		SET name = $1, type = $2, product_id = $3, buy_qty = $4, get_qty = $5, promo_price = $6,
+			start_at = $7, end_at = $8, days_of_week = $9, start_time = $10, end_time = $11, is_active = $12
+		WHERE id = $13
+		RETURNING id, created_at
+	`,
+		updated.Name,
+		updated.Type,
+		updated.ProductID,
+		updated.BuyQty,
+		updated.GetQty,
+		updated.PromoPrice,
+		updated.StartAt,
+		updated.EndAt,
+		pq.Array(updated.DaysOfWeek),
+		updated.StartTime,
+		updated.EndTime,
+		updated.IsActive,
+		id,
+	).Scan(&updated.ID, &updated.CreatedAt)
+	if err != nil {
+		return models.Promotion{}, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM promotion_items WHERE promotion_id = $1`, id); err != nil {
+		return models.Promotion{}, err
+	}
+
+	if err := insertPromotionItems(tx, id, updated.Items); err != nil {
+		return models.Promotion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Promotion{}, err
+	}
+
+	return updated, nil
+}
+
+// ===== DELETE =====
+// Promo yang sudah tercatat di transaksi tidak bisa dihapus, nonaktifkan saja
+func (r *PromotionRepository) Delete(id int) error {
+	var used bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM transaction_details WHERE promotion_id = $1)
+	`, id).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrPromotionInUse
+	}
+
+	result, err := r.db.Exec(`DELETE FROM promotions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PromotionRepository) query(query string, args ...interface{}) ([]models.Promotion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]models.Promotion, 0)
+	var ids []int
+
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+		ids = append(ids, p.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range promotions {
+		promotions[i].Items = items[promotions[i].ID]
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionRepository) getItems(promotionIDs []int) (map[int][]models.PromotionItem, error) {
+	result := make(map[int][]models.PromotionItem)
+	if len(promotionIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT promotion_id, product_id, quantity
+		FROM promotion_items
+		WHERE promotion_id = ANY($1)
+		ORDER BY product_id
+	`, pq.Array(promotionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var promotionID int
+		var item models.PromotionItem
+		if err := rows.Scan(&promotionID, &item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		result[promotionID] = append(result[promotionID], item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func insertPromotionItems(tx *sql.Tx, promotionID int, items []models.PromotionItem) error {
+	for _, item := range items {
+		_, err := tx.Exec(`
+			INSERT INTO promotion_items (promotion_id, product_id, quantity)
+			VALUES ($1, $2, $3)
+		`, promotionID, item.ProductID, item.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
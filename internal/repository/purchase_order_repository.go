@@ -0,0 +1,519 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrPurchaseOrderStatus           = errors.New("purchase order status does not allow this action")
+	ErrPurchaseOrderSupplierNotFound = errors.New("supplier not found")
+	ErrPurchaseOrderProductNotFound  = errors.New("product not found")
+	ErrReceiptProductNotInOrder      = errors.New("product not in purchase order")
+	ErrReceiptQuantityExceeded       = errors.New("received quantity exceeds quantity ordered")
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{
+		db: db,
+	}
+}
+
+const purchaseOrderColumns = `po.id, po.supplier_id, s.name, po.status, po.note, po.created_by,
+	COALESCE((SELECT SUM(l.quantity * l.unit_cost) FROM purchase_order_lines l WHERE l.purchase_order_id = po.id), 0),
+	po.created_at, po.updated_at`
+
+func scanPurchaseOrder(row rowScanner) (models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	err := row.Scan(
+		&po.ID,
+		&po.SupplierID,
+		&po.SupplierName,
+		&po.Status,
+		&po.Note,
+		&po.CreatedBy,
+		&po.TotalCost,
+		&po.CreatedAt,
+		&po.UpdatedAt,
+	)
+	return po, err
+}
+
+// ===== GET ALL =====
+// filter kosong berarti semua PO, baris dan penerimaan tidak ikut diambil
+func (r *PurchaseOrderRepository) GetAll(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.SupplierID > 0 {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("po.supplier_id = $%d", len(args)))
+	}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("po.status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+purchaseOrderColumns+`
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id`+where+`
+		ORDER BY po.id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, po)
+	}
+
+	return orders, rows.Err()
+}
+
+// ===== GET BY ID =====
+// GetByID mengambil PO beserta baris dan riwayat penerimaannya
+func (r *PurchaseOrderRepository) GetByID(id int) (models.PurchaseOrder, error) {
+	po, err := scanPurchaseOrder(r.db.QueryRow(`
+		SELECT `+purchaseOrderColumns+`
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		WHERE po.id = $1
+	`, id))
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if po.Lines, err = r.getLines(id); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if po.Receipts, err = r.getReceipts(id); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return po, nil
+}
+
+// ===== CREATE =====
+// Create menyimpan PO baru berstatus draft
+func (r *PurchaseOrderRepository) Create(po models.PurchaseOrder) (models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkPurchaseOrderRefs(tx, po); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO purchase_orders (supplier_id, status, note, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, po.SupplierID, models.PurchaseOrderStatusDraft, po.Note, po.CreatedBy).Scan(&po.ID)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := insertPurchaseOrderLines(tx, po.ID, po.Lines); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return r.GetByID(po.ID)
+}
+
+// ===== UPDATE =====
+// Update mengganti supplier, catatan dan baris PO, hanya untuk status draft
+func (r *PurchaseOrderRepository) Update(id int, po models.PurchaseOrder) (models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id, models.PurchaseOrderStatusDraft); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := checkPurchaseOrderRefs(tx, po); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchase_orders
+		SET supplier_id = $1, note = $2, created_by = $3, updated_at = NOW()
+		WHERE id = $4
+	`, po.SupplierID, po.Note, po.CreatedBy, id)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM purchase_order_lines WHERE purchase_order_id = $1`, id); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := insertPurchaseOrderLines(tx, id, po.Lines); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// ===== STATUS =====
+// SetStatus memindahkan PO ke status to jika status saat ini salah satu dari from
+func (r *PurchaseOrderRepository) SetStatus(id int, to string, from ...string) (models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id, from...); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchase_orders
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`, to, id)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// ===== RECEIVE =====
+// Receive mencatat penerimaan barang untuk PO sent / partially_received: stok product
+// bertambah (kartu stok reason receipt), received_qty baris PO naik dan status PO menjadi
+// partially_received atau received, semuanya dalam satu DB transaction.
+// Baris penerimaan tanpa UnitCost memakai unit_cost di PO, cost_price product menjadi
+// rata-rata tertimbang stok lama dan barang yang diterima.
+func (r *PurchaseOrderRepository) Receive(id int, receipt models.GoodsReceiptRequest) (models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id,
+		models.PurchaseOrderStatusSent,
+		models.PurchaseOrderStatusPartiallyReceived,
+	); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	type orderLine struct {
+		id        int
+		remaining int
+		unitCost  int
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, product_id, quantity - received_qty, unit_cost
+		FROM purchase_order_lines
+		WHERE purchase_order_id = $1
+		FOR UPDATE
+	`, id)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	lines := make(map[int]orderLine)
+	for rows.Next() {
+		var productID int
+		var l orderLine
+		if err := rows.Scan(&l.id, &productID, &l.remaining, &l.unitCost); err != nil {
+			rows.Close()
+			return models.PurchaseOrder{}, err
+		}
+		lines[productID] = l
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	lineIDs := make([]int, len(receipt.Lines))
+	productIDs := make([]int, len(receipt.Lines))
+	quantities := make([]int, len(receipt.Lines))
+	costs := make([]int, len(receipt.Lines))
+
+	for i, item := range receipt.Lines {
+		l, ok := lines[item.ProductID]
+		if !ok {
+			return models.PurchaseOrder{}, fmt.Errorf("%w: product id %d", ErrReceiptProductNotInOrder, item.ProductID)
+		}
+		if item.Quantity > l.remaining {
+			return models.PurchaseOrder{}, fmt.Errorf("%w: product id %d, remaining %d", ErrReceiptQuantityExceeded, item.ProductID, l.remaining)
+		}
+
+		cost := l.unitCost
+		if item.UnitCost != nil {
+			cost = *item.UnitCost
+		}
+
+		lineIDs[i] = l.id
+		productIDs[i] = item.ProductID
+		quantities[i] = item.Quantity
+		costs[i] = cost
+	}
+
+	// product dikunci urut ID supaya tidak deadlock dengan checkout
+	if _, err := tx.Exec(`
+		SELECT id FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE
+	`, pq.Array(productIDs)); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	var receiptID int
+	err = tx.QueryRow(`
+		INSERT INTO goods_receipts (purchase_order_id, received_by, note)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, id, receipt.ReceivedBy, receipt.Note).Scan(&receiptID)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO goods_receipt_lines (goods_receipt_id, purchase_order_line_id, product_id, quantity, unit_cost)
+		SELECT $1, line_id, product_id, quantity, unit_cost
+		FROM unnest($2::int[], $3::int[], $4::int[], $5::int[]) AS v(line_id, product_id, quantity, unit_cost)
+	`, receiptID, pq.Array(lineIDs), pq.Array(productIDs), pq.Array(quantities), pq.Array(costs))
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchase_order_lines l
+		SET received_qty = l.received_qty + v.quantity
+		FROM unnest($1::int[], $2::int[]) AS v(line_id, quantity)
+		WHERE l.id = v.line_id
+	`, pq.Array(lineIDs), pq.Array(quantities))
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	// harga pokok rata-rata tertimbang dihitung dari stok sebelum penerimaan
+	// (stok negatif dianggap 0), jadi harus sebelum moveStock
+	_, err = tx.Exec(`
+		UPDATE products p
+		SET cost_price = ROUND(
+			(GREATEST(p.stock, 0)::numeric * p.cost_price + v.quantity * v.unit_cost)
+			/ (GREATEST(p.stock, 0) + v.quantity)
+		)
+		FROM unnest($1::int[], $2::int[], $3::int[]) AS v(product_id, quantity, unit_cost)
+		WHERE p.id = v.product_id
+	`, pq.Array(productIDs), pq.Array(quantities), pq.Array(costs))
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	err = moveStock(tx, productIDs, quantities, models.StockMovement{
+		Reason:        models.StockReasonReceipt,
+		ReferenceType: models.StockReferenceReceipt,
+		ReferenceID:   &receiptID,
+		CreatedBy:     receipt.ReceivedBy,
+		Note:          fmt.Sprintf("PO #%d", id),
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchase_orders
+		SET status = CASE
+				WHEN EXISTS (
+					SELECT 1 FROM purchase_order_lines
+					WHERE purchase_order_id = $1 AND received_qty < quantity
+				) THEN $2
+				ELSE $3
+			END,
+			updated_at = NOW()
+		WHERE id = $1
+	`, id, models.PurchaseOrderStatusPartiallyReceived, models.PurchaseOrderStatusReceived)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *PurchaseOrderRepository) getLines(orderID int) ([]models.PurchaseOrderLine, error) {
+	rows, err := r.db.Query(`
+		SELECT l.id, l.product_id, p.name, l.quantity, l.unit_cost, l.received_qty
+		FROM purchase_order_lines l
+		JOIN products p ON p.id = l.product_id
+		WHERE l.purchase_order_id = $1
+		ORDER BY l.id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]models.PurchaseOrderLine, 0)
+	for rows.Next() {
+		var l models.PurchaseOrderLine
+		if err := rows.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.Quantity, &l.UnitCost, &l.ReceivedQty); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *PurchaseOrderRepository) getReceipts(orderID int) ([]models.GoodsReceipt, error) {
+	rows, err := r.db.Query(`
+		SELECT g.id, g.received_by, g.note, g.created_at, l.product_id, l.quantity, l.unit_cost
+		FROM goods_receipts g
+		JOIN goods_receipt_lines l ON l.goods_receipt_id = g.id
+		WHERE g.purchase_order_id = $1
+		ORDER BY g.id, l.id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []models.GoodsReceipt
+	for rows.Next() {
+		var g models.GoodsReceipt
+		var l models.GoodsReceiptLine
+		if err := rows.Scan(&g.ID, &g.ReceivedBy, &g.Note, &g.CreatedAt, &l.ProductID, &l.Quantity, &l.UnitCost); err != nil {
+			return nil, err
+		}
+
+		if n := len(receipts); n == 0 || receipts[n-1].ID != g.ID {
+			g.PurchaseOrderID = orderID
+			receipts = append(receipts, g)
+		}
+		last := &receipts[len(receipts)-1]
+		last.Lines = append(last.Lines, l)
+	}
+
+	return receipts, rows.Err()
+}
+
+// lockPurchaseOrder mengunci PO dan memastikan statusnya salah satu dari allowed
+func lockPurchaseOrder(tx *sql.Tx, id int, allowed ...string) error {
+	var status string
+	err := tx.QueryRow(`SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range allowed {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: status %s", ErrPurchaseOrderStatus, status)
+}
+
+// checkPurchaseOrderRefs memastikan supplier dan semua product di baris PO ada
+func checkPurchaseOrderRefs(tx *sql.Tx, po models.PurchaseOrder) error {
+	var exists bool
+	err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM suppliers WHERE id = $1)`, po.SupplierID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrPurchaseOrderSupplierNotFound
+	}
+
+	ids := make([]int, len(po.Lines))
+	for i, l := range po.Lines {
+		ids[i] = l.ProductID
+	}
+
+	rows, err := tx.Query(`SELECT id FROM products WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: ids %v", ErrPurchaseOrderProductNotFound, missing)
+	}
+	return nil
+}
+
+func insertPurchaseOrderLines(tx *sql.Tx, orderID int, lines []models.PurchaseOrderLine) error {
+	productIDs := make([]int, len(lines))
+	quantities := make([]int, len(lines))
+	costs := make([]int, len(lines))
+	for i, l := range lines {
+		productIDs[i] = l.ProductID
+		quantities[i] = l.Quantity
+		costs[i] = l.UnitCost
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO purchase_order_lines (purchase_order_id, product_id, quantity, unit_cost)
+		SELECT $1, product_id, quantity, unit_cost
+		FROM unnest($2::int[], $3::int[], $4::int[]) WITH ORDINALITY AS v(product_id, quantity, unit_cost, ord)
+		ORDER BY ord
+	`, orderID, pq.Array(productIDs), pq.Array(quantities), pq.Array(costs))
+	return err
+}
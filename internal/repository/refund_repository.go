@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+)
+
+var (
+	ErrInvalidRefundItem      = errors.New("transaction detail not found in this transaction")
+	ErrRefundQuantityExceeded = errors.New("refund quantity exceeds quantity sold")
+)
+
+type RefundRepository struct {
+	db *sql.DB
+}
+
+func NewRefundRepository(db *sql.DB) *RefundRepository {
+	return &RefundRepository{
+		db: db,
+	}
+}
+
+type refundableLine struct {
+	productID      int
+	quantity       int
+	lineTotal      int
+	refundedQty    int
+	refundedAmount int
+}
+
+// CreateRefund mencatat refund, mengembalikan stok, dan memastikan
+// jumlah refund tidak melebihi jumlah yang terjual dalam satu DB transaction.
+func (repo *RefundRepository) CreateRefund(transactionID int, req models.RefundRequest) (*models.Refund, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// lock transaksi asal supaya refund paralel antri
+	var status string
+	var taxInclusive bool
+	err = tx.QueryRow(`
+		SELECT status, tax_inclusive
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`, transactionID).Scan(&status, &taxInclusive)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.TransactionStatusVoided {
+		return nil, ErrTransactionVoided
+	}
+
+	// nominal yang dibayar per baris = subtotal + service charge (+ pajak jika exclusive)
+	rows, err := tx.Query(`
+		SELECT
+			td.id,
+			td.product_id,
+			td.quantity,
+			td.subtotal + td.service_charge_amount + CASE WHEN $2 THEN 0 ELSE td.tax_amount END,
+			COALESCE(SUM(rd.quantity), 0),
+			COALESCE(SUM(rd.amount), 0)
+		FROM transaction_details td
+		LEFT JOIN refund_details rd ON rd.transaction_detail_id = td.id
+		WHERE td.transaction_id = $1
+		GROUP BY td.id, td.product_id, td.quantity, td.subtotal, td.service_charge_amount, td.tax_amount
+	`, transactionID, taxInclusive)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(map[int]refundableLine)
+	for rows.Next() {
+		var id int
+		var l refundableLine
+		if err := rows.Scan(&id, &l.productID, &l.quantity, &l.lineTotal, &l.refundedQty, &l.refundedAmount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lines[id] = l
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	// gabungkan baris refund dengan detail yang sama
+	requested := make(map[int]int)
+	var order []int
+	for _, item := range req.Items {
+		if _, ok := requested[item.TransactionDetailID]; !ok {
+			order = append(order, item.TransactionDetailID)
+		}
+		requested[item.TransactionDetailID] += item.Quantity
+	}
+
+	totalAmount := 0
+	details := make([]models.RefundDetail, 0, len(order))
+
+	for _, detailID := range order {
+		qty := requested[detailID]
+
+		line, ok := lines[detailID]
+		if !ok {
+			return nil, fmt.Errorf("%w: id %d", ErrInvalidRefundItem, detailID)
+		}
+
+		remaining := line.quantity - line.refundedQty
+		if qty > remaining {
+			return nil, fmt.Errorf("%w: detail %d, remaining %d", ErrRefundQuantityExceeded, detailID, remaining)
+		}
+
+		// refund terakhir mengambil sisa nominal supaya tidak ada selisih pembulatan
+		amount := line.lineTotal * qty / line.quantity
+		if qty == remaining {
+			amount = line.lineTotal - line.refundedAmount
+		}
+		totalAmount += amount
+
+		details = append(details, models.RefundDetail{
+			TransactionDetailID: detailID,
+			ProductID:           line.productID,
+			Quantity:            qty,
+			Amount:              amount,
+		})
+	}
+
+	refund := models.Refund{
+		TransactionID: transactionID,
+		TotalAmount:   totalAmount,
+		Reason:        req.Reason,
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO refunds (transaction_id, total_amount, reason)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, transactionID, totalAmount, req.Reason).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int, len(details))
+	deltas := make([]int, len(details))
+	for i, d := range details {
+		productIDs[i] = d.ProductID
+		deltas[i] = d.Quantity
+	}
+
+	err = moveStock(tx, productIDs, deltas, models.StockMovement{
+		Reason:        models.StockReasonRefund,
+		ReferenceType: models.StockReferenceRefund,
+		ReferenceID:   &refund.ID,
+		Note:          req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stmtDetail, err := tx.Prepare(`
+		INSERT INTO refund_details (refund_id, transaction_detail_id, product_id, quantity, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmtDetail.Close()
+
+	for i := range details {
+		details[i].RefundID = refund.ID
+
+		err = stmtDetail.QueryRow(
+			refund.ID,
+			details[i].TransactionDetailID,
+			details[i].ProductID,
+			details[i].Quantity,
+			details[i].Amount,
+		).Scan(&details[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	refund.Details = details
+	return &refund, nil
+}
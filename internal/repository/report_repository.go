@@ -2,6 +2,9 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"kasir-api/internal/models"
+	"time"
 )
 
 type ReportRepository struct {
@@ -12,31 +15,78 @@ func NewReportRepository(db *sql.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
-func (r *ReportRepository) GetTodaySummary() (totalRevenue int, totalTransaksi int, err error) {
-	err = r.db.QueryRow(`
+// GetTodaySummary mengisi ringkasan penjualan hari ini, transaksi void tidak dihitung.
+// TotalRevenue adalah penjualan nett (gross - diskon) tanpa service charge dan pajak
+// exclusive, dikurangi porsi subtotal baris yang di-refund, sehingga sebanding dengan
+// GrossRevenue dan TotalDiscount. TotalRefund adalah uang yang dikembalikan ke customer
+// (termasuk service charge dan pajak).
+func (r *ReportRepository) GetTodaySummary() (*models.SalesReport, error) {
+	var report models.SalesReport
+	var netRefund int
+
+	err := r.db.QueryRow(`
 		SELECT
-			COALESCE(SUM(total_amount), 0),
-			COUNT(*)
+			COALESCE(SUM(gross_amount), 0),
+			COALESCE(SUM(discount_amount), 0),
+			COALESCE(SUM(service_charge_amount), 0),
+			COALESCE(SUM(tax_amount), 0),
+			COUNT(*),
+			COALESCE((
+				SELECT SUM(rf.total_amount)
+				FROM refunds rf
+				JOIN transactions rt ON rt.id = rf.transaction_id
+				WHERE DATE(rf.created_at) = CURRENT_DATE
+					AND rt.status <> $1
+			), 0),
+			COALESCE((
+				SELECT SUM(td.subtotal * rd.quantity / td.quantity)
+				FROM refund_details rd
+				JOIN refunds rf ON rf.id = rd.refund_id
+				JOIN transaction_details td ON td.id = rd.transaction_detail_id
+				JOIN transactions rt ON rt.id = rf.transaction_id
+				WHERE DATE(rf.created_at) = CURRENT_DATE
+					AND rt.status <> $1
+			), 0)
 		FROM transactions
 		WHERE DATE(created_at) = CURRENT_DATE
-	`).Scan(&totalRevenue, &totalTransaksi)
+			AND status <> $1
+	`, models.TransactionStatusVoided).Scan(
+		&report.GrossRevenue,
+		&report.TotalDiscount,
+		&report.TotalServiceCharge,
+		&report.TotalTax,
+		&report.TotalTransaksi,
+		&report.TotalRefund,
+		&netRefund,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	return
+	report.TotalRevenue = report.GrossRevenue - report.TotalDiscount - netRefund
+	return &report, nil
 }
 
+// GetTodayBestSeller mengelompokkan per product_id, nama diambil dari snapshot
+// detail terakhir supaya product yang di-rename tidak terpecah
 func (r *ReportRepository) GetTodayBestSeller() (nama string, qty int, err error) {
 	err = r.db.QueryRow(`
 		SELECT
-			p.name,
-			SUM(td.quantity) AS qty_terjual
+			(ARRAY_AGG(td.product_name ORDER BY td.id DESC))[1],
+			SUM(td.quantity - COALESCE(rd.qty, 0)) AS qty_terjual
 		FROM transaction_details td
 		JOIN transactions t ON t.id = td.transaction_id
-		JOIN products p ON p.id = td.product_id
+		LEFT JOIN (
+			SELECT transaction_detail_id, SUM(quantity) AS qty
+			FROM refund_details
+			GROUP BY transaction_detail_id
+		) rd ON rd.transaction_detail_id = td.id
 		WHERE DATE(t.created_at) = CURRENT_DATE
-		GROUP BY p.name
+			AND t.status <> $1
+		GROUP BY td.product_id
 		ORDER BY qty_terjual DESC
 		LIMIT 1
-	`).Scan(&nama, &qty)
+	`, models.TransactionStatusVoided).Scan(&nama, &qty)
 
 	if err == sql.ErrNoRows {
 		return "", 0, nil
@@ -44,3 +94,160 @@ func (r *ReportRepository) GetTodayBestSeller() (nama string, qty int, err error
 
 	return
 }
+
+// GetTodayPaymentBreakdown menjumlahkan pembayaran hari ini per metode,
+// nominal cash sudah dikurangi kembalian
+func (r *ReportRepository) GetTodayPaymentBreakdown() ([]models.PaymentMethodSummary, error) {
+	rows, err := r.db.Query(`
+		SELECT
+			tp.method,
+			SUM(tp.amount),
+			COUNT(DISTINCT tp.transaction_id)
+		FROM transaction_payments tp
+		JOIN transactions t ON t.id = tp.transaction_id
+		WHERE DATE(t.created_at) = CURRENT_DATE
+			AND t.status <> $1
+		GROUP BY tp.method
+		ORDER BY tp.method
+	`, models.TransactionStatusVoided)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]models.PaymentMethodSummary, 0)
+	for rows.Next() {
+		var s models.PaymentMethodSummary
+		if err := rows.Scan(&s.Method, &s.TotalAmount, &s.TotalTransaksi); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var totalChange int
+	err = r.db.QueryRow(`
+		SELECT COALESCE(SUM(change_amount), 0)
+		FROM transactions
+		WHERE DATE(created_at) = CURRENT_DATE
+			AND status <> $1
+	`, models.TransactionStatusVoided).Scan(&totalChange)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		if summaries[i].Method == models.PaymentMethodCash {
+			summaries[i].TotalAmount -= totalChange
+		}
+	}
+
+	return summaries, nil
+}
+
+// GetTaxSummary merangkum pajak dan service charge transaksi dalam rentang [start, end).
+// Pajak atas barang yang di-refund dihitung proporsional terhadap qty refund.
+func (r *ReportRepository) GetTaxSummary(start, end time.Time) (*models.TaxSummary, error) {
+	summary := models.TaxSummary{
+		StartDate: start,
+		EndDate:   end,
+	}
+
+	err := r.db.QueryRow(`
+		SELECT
+			COUNT(DISTINCT t.id),
+			COALESCE(SUM(CASE WHEN td.tax_class <> $4 THEN td.subtotal + td.service_charge_amount ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN td.tax_class = $4 THEN td.subtotal + td.service_charge_amount ELSE 0 END), 0),
+			COALESCE(SUM(td.service_charge_amount), 0),
+			COALESCE(SUM(td.tax_amount), 0),
+			COALESCE(SUM(td.tax_amount * COALESCE(rd.qty, 0) / td.quantity), 0)
+		FROM transactions t
+		JOIN transaction_details td ON td.transaction_id = t.id
+		LEFT JOIN (
+			SELECT transaction_detail_id, SUM(quantity) AS qty
+			FROM refund_details
+			GROUP BY transaction_detail_id
+		) rd ON rd.transaction_detail_id = td.id
+		WHERE t.created_at >= $1
+			AND t.created_at < $2
+			AND t.status <> $3
+	`, start, end, models.TransactionStatusVoided, models.TaxClassExempt).Scan(
+		&summary.TotalTransaksi,
+		&summary.TaxableAmount,
+		&summary.ExemptAmount,
+		&summary.ServiceChargeTotal,
+		&summary.TaxAmount,
+		&summary.TaxRefunded,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.NetTaxAmount = summary.TaxAmount - summary.TaxRefunded
+	return &summary, nil
+}
+
+// profitGroups memetakan group_by ke ekspresi key dan label. Nama product dari snapshot
+// detail, category mengikuti category product saat ini karena tidak di-snapshot
+var profitGroups = map[string]struct{ key, label, order string }{
+	models.ProfitGroupByDay:      {"TO_CHAR(t.created_at, 'YYYY-MM-DD')", "TO_CHAR(t.created_at, 'YYYY-MM-DD')", "key"},
+	models.ProfitGroupByProduct:  {"td.product_id::text", "MAX(td.product_name)", "gross_profit DESC, key"},
+	models.ProfitGroupByCategory: {"COALESCE(p.category_id, 0)::text", "COALESCE(MAX(c.name), 'uncategorized')", "gross_profit DESC, key"},
+}
+
+// GetProfit menghitung revenue, COGS dan laba kotor transaksi dalam rentang [start, end)
+// per groupBy. Revenue baris adalah subtotal dikurangi pajak inklusif, revenue dan COGS
+// untuk qty yang di-refund dikeluarkan secara proporsional. Transaksi void tidak dihitung.
+func (r *ReportRepository) GetProfit(start, end time.Time, groupBy string) ([]models.ProfitRow, error) {
+	group, ok := profitGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown profit group %q", groupBy)
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT key, label, quantity, revenue, cogs, revenue - cogs AS gross_profit
+		FROM (
+			SELECT
+				%s AS key,
+				%s AS label,
+				COALESCE(SUM(td.quantity - COALESCE(rd.qty, 0)), 0) AS quantity,
+				COALESCE(SUM(
+					(td.subtotal - CASE WHEN t.tax_inclusive THEN td.tax_amount ELSE 0 END)
+					* (td.quantity - COALESCE(rd.qty, 0)) / td.quantity
+				), 0) AS revenue,
+				COALESCE(SUM(td.unit_cost * (td.quantity - COALESCE(rd.qty, 0))), 0) AS cogs
+			FROM transactions t
+			JOIN transaction_details td ON td.transaction_id = t.id
+			LEFT JOIN products p ON p.id = td.product_id
+			LEFT JOIN categories c ON c.id = p.category_id
+			LEFT JOIN (
+				SELECT transaction_detail_id, SUM(quantity) AS qty
+				FROM refund_details
+				GROUP BY transaction_detail_id
+			) rd ON rd.transaction_detail_id = td.id
+			WHERE t.created_at >= $1
+				AND t.created_at < $2
+				AND t.status <> $3
+			GROUP BY 1
+		) g
+		ORDER BY %s
+	`, group.key, group.label, group.order), start, end, models.TransactionStatusVoided)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.ProfitRow, 0)
+	for rows.Next() {
+		var row models.ProfitRow
+		if err := rows.Scan(&row.Key, &row.Label, &row.Quantity, &row.Revenue, &row.COGS, &row.GrossProfit); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
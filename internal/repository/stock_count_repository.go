@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrStockCountNotOpen      = errors.New("stock count already posted or cancelled")
+	ErrStockCountAlreadyOpen  = errors.New("another stock count is still open")
+	ErrStockCountItemNotFound = errors.New("product not in stock count")
+)
+
+type StockCountRepository struct {
+	db *sql.DB
+}
+
+func NewStockCountRepository(db *sql.DB) *StockCountRepository {
+	return &StockCountRepository{
+		db: db,
+	}
+}
+
+const stockCountColumns = `id, status, note, created_by, posted_by, created_at, closed_at`
+
+func scanStockCount(row rowScanner) (models.StockCount, error) {
+	var c models.StockCount
+	err := row.Scan(
+		&c.ID,
+		&c.Status,
+		&c.Note,
+		&c.CreatedBy,
+		&c.PostedBy,
+		&c.CreatedAt,
+		&c.ClosedAt,
+	)
+	return c, err
+}
+
+// ===== GET ALL =====
+// status kosong berarti tanpa filter, item tidak ikut diambil
+func (r *StockCountRepository) GetAll(status string) ([]models.StockCount, error) {
+	query := `SELECT ` + stockCountColumns + ` FROM stock_counts`
+	var args []interface{}
+
+	if status != "" {
+		args = append(args, status)
+		query += ` WHERE status = $1`
+	}
+
+	rows, err := r.db.Query(query+` ORDER BY id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]models.StockCount, 0)
+	for rows.Next() {
+		c, err := scanStockCount(rows)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// ===== GET BY ID =====
+// GetByID mengambil sesi beserta item, selisih dan ringkasannya
+func (r *StockCountRepository) GetByID(id int) (models.StockCount, error) {
+	c, err := scanStockCount(r.db.QueryRow(`
+		SELECT `+stockCountColumns+`
+		FROM stock_counts
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT i.product_id, p.name, i.system_stock, i.counted_qty, i.counted_by, i.counted_at
+		FROM stock_count_items i
+		JOIN products p ON p.id = i.product_id
+		WHERE i.stock_count_id = $1
+		ORDER BY i.product_id
+	`, id)
+	if err != nil {
+		return models.StockCount{}, err
+	}
+	defer rows.Close()
+
+	summary := models.StockCountSummary{}
+	c.Items = make([]models.StockCountItem, 0)
+
+	for rows.Next() {
+		var item models.StockCountItem
+		err := rows.Scan(
+			&item.ProductID,
+			&item.ProductName,
+			&item.SystemStock,
+			&item.CountedQty,
+			&item.CountedBy,
+			&item.CountedAt,
+		)
+		if err != nil {
+			return models.StockCount{}, err
+		}
+
+		summary.TotalItems++
+		if item.CountedQty != nil {
+			variance := *item.CountedQty - item.SystemStock
+			item.Variance = &variance
+
+			summary.CountedItems++
+			summary.TotalVariance += variance
+			if variance != 0 {
+				summary.VarianceItems++
+			}
+		}
+
+		c.Items = append(c.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	c.Summary = &summary
+	return c, nil
+}
+
+// ===== OPEN =====
+// Open membuat sesi baru dan men-snapshot stok semua product
+func (r *StockCountRepository) Open(count models.StockCount) (models.StockCount, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.StockCount{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_counts (note, created_by)
+		VALUES ($1, $2)
+		RETURNING id
+	`, count.Note, count.CreatedBy).Scan(&count.ID)
+	if isUniqueViolation(err) {
+		return models.StockCount{}, ErrStockCountAlreadyOpen
+	}
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO stock_count_items (stock_count_id, product_id, system_stock)
+		SELECT $1, id, stock
+		FROM products
+	`, count.ID)
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	return r.GetByID(count.ID)
+}
+
+// ===== SUBMIT COUNTS =====
+// SubmitCounts menyimpan hasil hitung sebagian. Sesi dikunci FOR SHARE sehingga
+// beberapa petugas bisa submit bersamaan, tetapi tidak bersamaan dengan posting.
+func (r *StockCountRepository) SubmitCounts(id int, submission models.StockCountSubmission) (models.StockCount, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.StockCount{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockStockCount(tx, id, "FOR SHARE"); err != nil {
+		return models.StockCount{}, err
+	}
+
+	productIDs := make([]int, len(submission.Items))
+	quantities := make([]int, len(submission.Items))
+	for i, entry := range submission.Items {
+		productIDs[i] = entry.ProductID
+		quantities[i] = entry.CountedQty
+	}
+
+	rows, err := tx.Query(`
+		UPDATE stock_count_items i
+		SET counted_qty = v.qty, counted_by = $4, counted_at = NOW()
+		FROM unnest($2::int[], $3::int[]) AS v(product_id, qty)
+		WHERE i.stock_count_id = $1 AND i.product_id = v.product_id
+		RETURNING i.product_id
+	`, id, pq.Array(productIDs), pq.Array(quantities), submission.CountedBy)
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	updated := make(map[int]bool)
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			rows.Close()
+			return models.StockCount{}, err
+		}
+		updated[productID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	var missing []int
+	for _, productID := range productIDs {
+		if !updated[productID] {
+			missing = append(missing, productID)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Ints(missing)
+		return models.StockCount{}, fmt.Errorf("%w: product ids %v", ErrStockCountItemNotFound, missing)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// ===== POST =====
+// Post menerapkan selisih item yang sudah dihitung ke stok product dan kartu stok
+// (reason opname) lalu menutup sesi, semuanya dalam satu DB transaction.
+// Item yang belum dihitung tidak mengubah stok.
+func (r *StockCountRepository) Post(id int, postedBy string) (models.StockCount, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.StockCount{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockStockCount(tx, id, "FOR UPDATE"); err != nil {
+		return models.StockCount{}, err
+	}
+
+	// product dikunci urut ID supaya tidak deadlock dengan checkout
+	rows, err := tx.Query(`
+		SELECT p.id, i.counted_qty - i.system_stock, p.stock
+		FROM stock_count_items i
+		JOIN products p ON p.id = i.product_id
+		WHERE i.stock_count_id = $1
+			AND i.counted_qty IS NOT NULL
+			AND i.counted_qty <> i.system_stock
+		ORDER BY p.id
+		FOR UPDATE OF p
+	`, id)
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	var productIDs, deltas, negative []int
+	for rows.Next() {
+		var productID, delta, stock int
+		if err := rows.Scan(&productID, &delta, &stock); err != nil {
+			rows.Close()
+			return models.StockCount{}, err
+		}
+
+		if stock+delta < 0 {
+			negative = append(negative, productID)
+		}
+		productIDs = append(productIDs, productID)
+		deltas = append(deltas, delta)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	if len(negative) > 0 {
+		return models.StockCount{}, fmt.Errorf("%w: product ids %v", ErrNegativeStock, negative)
+	}
+
+	err = moveStock(tx, productIDs, deltas, models.StockMovement{
+		Reason:        models.StockReasonOpname,
+		ReferenceType: models.StockReferenceStockCount,
+		ReferenceID:   &id,
+		CreatedBy:     postedBy,
+	})
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE stock_counts
+		SET status = $1, posted_by = $2, closed_at = NOW()
+		WHERE id = $3
+	`, models.StockCountStatusPosted, postedBy, id)
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// ===== CANCEL =====
+// Cancel menutup sesi tanpa mengubah stok
+func (r *StockCountRepository) Cancel(id int) (models.StockCount, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.StockCount{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockStockCount(tx, id, "FOR UPDATE"); err != nil {
+		return models.StockCount{}, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE stock_counts
+		SET status = $1, closed_at = NOW()
+		WHERE id = $2
+	`, models.StockCountStatusCancelled, id)
+	if err != nil {
+		return models.StockCount{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.StockCount{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// lockStockCount mengunci sesi dan memastikan statusnya masih open
+func lockStockCount(tx *sql.Tx, id int, lock string) error {
+	var status string
+	err := tx.QueryRow(`SELECT status FROM stock_counts WHERE id = $1 `+lock, id).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	if status != models.StockCountStatusOpen {
+		return ErrStockCountNotOpen
+	}
+	return nil
+}
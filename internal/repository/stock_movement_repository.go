@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var ErrNegativeStock = errors.New("stock cannot go below zero")
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{
+		db: db,
+	}
+}
+
+// GetByProduct mengambil kartu stok satu product, terbaru lebih dulu
+func (r *StockMovementRepository) GetByProduct(filter models.StockMovementFilter) ([]models.StockMovement, int, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, filter.ProductID).Scan(&exists)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, sql.ErrNoRows
+	}
+
+	args := []interface{}{filter.ProductID}
+	conditions := []string{"product_id = $1"}
+
+	if filter.StartDate != nil {
+		args = append(args, *filter.StartDate)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.EndDate != nil {
+		args = append(args, *filter.EndDate)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if filter.Reason != "" {
+		args = append(args, filter.Reason)
+		conditions = append(conditions, fmt.Sprintf("reason = $%d", len(args)))
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM stock_movements`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, product_id, delta, balance, reason, reference_type, reference_id, created_by, note, created_at
+		FROM stock_movements%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(
+			&m.ID,
+			&m.ProductID,
+			&m.Delta,
+			&m.Balance,
+			&m.Reason,
+			&m.ReferenceType,
+			&m.ReferenceID,
+			&m.CreatedBy,
+			&m.Note,
+			&m.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+// CreateAdjustment menerapkan delta relatif terhadap stok saat ini (baris product dikunci)
+// dan mencatat dokumen penyesuaian serta kartu stoknya dalam satu DB transaction
+func (r *StockMovementRepository) CreateAdjustment(adj models.StockAdjustment) (models.StockAdjustment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.StockAdjustment{}, err
+	}
+	defer tx.Rollback()
+
+	var stock int
+	err = tx.QueryRow(`
+		SELECT stock
+		FROM products
+		WHERE id = $1
+		FOR UPDATE
+	`, adj.ProductID).Scan(&stock)
+	if err != nil {
+		return models.StockAdjustment{}, err
+	}
+
+	if stock+adj.Delta < 0 {
+		return models.StockAdjustment{}, fmt.Errorf("%w: current stock %d", ErrNegativeStock, stock)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_adjustments (product_id, delta, reason, note, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, adj.ProductID, adj.Delta, adj.Reason, adj.Note, adj.CreatedBy).Scan(&adj.ID, &adj.CreatedAt)
+	if err != nil {
+		return models.StockAdjustment{}, err
+	}
+
+	err = moveStock(tx, []int{adj.ProductID}, []int{adj.Delta}, models.StockMovement{
+		Reason:        models.StockReasonAdjustment,
+		ReferenceType: models.StockReferenceAdjustment,
+		ReferenceID:   &adj.ID,
+		CreatedBy:     adj.CreatedBy,
+		Note:          adj.Note,
+	})
+	if err != nil {
+		return models.StockAdjustment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.StockAdjustment{}, err
+	}
+
+	adj.Balance = stock + adj.Delta
+	return adj, nil
+}
+
+// moveStock menambah stok productIDs sebesar deltas (negatif = berkurang) dan mencatat
+// kartu stoknya dalam satu statement, sehingga selalu berada di DB transaction yang sama.
+// Reason, reference, CreatedBy dan Note diambil dari info. Delta untuk product yang sama
+// dijumlahkan, delta 0 tidak dicatat.
+func moveStock(tx *sql.Tx, productIDs, deltas []int, info models.StockMovement) error {
+	// product dikunci urut ID lebih dulu (no-op jika pemanggil sudah mengunci), urutan lock
+	// UPDATE ... FROM unnest bergantung pada plan dan bisa deadlock dengan checkout paralel
+	_, err := tx.Exec(`
+		SELECT id
+		FROM products
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR UPDATE
+	`, pq.Array(productIDs))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		WITH v AS (
+			SELECT id, SUM(delta) AS delta
+			FROM unnest($1::int[], $2::int[]) AS u(id, delta)
+			GROUP BY id
+			HAVING SUM(delta) <> 0
+		), moved AS (
+			UPDATE products p
+			SET stock = p.stock + v.delta
+			FROM v
+			WHERE p.id = v.id
+			RETURNING p.id, v.delta, p.stock
+		)
+		INSERT INTO stock_movements (product_id, delta, balance, reason, reference_type, reference_id, created_by, note)
+		SELECT id, delta, stock, $3, $4, $5, $6, $7
+		FROM moved
+		ORDER BY id
+	`,
+		pq.Array(productIDs),
+		pq.Array(deltas),
+		info.Reason,
+		info.ReferenceType,
+		info.ReferenceID,
+		info.CreatedBy,
+		info.Note,
+	)
+	return err
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/internal/models"
+)
+
+var ErrSupplierInUse = errors.New("supplier still has purchase orders")
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{
+		db: db,
+	}
+}
+
+// ===== GET ALL =====
+func (r *SupplierRepository) GetAll() ([]models.Supplier, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, phone, email, address, created_at
+		FROM suppliers
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+
+	for rows.Next() {
+		var s models.Supplier
+		if err := rows.Scan(
+			&s.ID,
+			&s.Name,
+			&s.Phone,
+			&s.Email,
+			&s.Address,
+			&s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+// ===== GET BY ID =====
+func (r *SupplierRepository) GetByID(id int) (models.Supplier, error) {
+	var s models.Supplier
+
+	err := r.db.QueryRow(`
+		SELECT id, name, phone, email, address, created_at
+		FROM suppliers
+		WHERE id = $1
+	`, id).Scan(
+		&s.ID,
+		&s.Name,
+		&s.Phone,
+		&s.Email,
+		&s.Address,
+		&s.CreatedAt,
+	)
+
+	if err != nil {
+		return models.Supplier{}, err
+	}
+
+	return s, nil
+}
+
+// ===== CREATE =====
+func (r *SupplierRepository) Create(supplier models.Supplier) (models.Supplier, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO suppliers (name, phone, email, address)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`,
+		supplier.Name,
+		supplier.Phone,
+		supplier.Email,
+		supplier.Address,
+	).Scan(&supplier.ID, &supplier.CreatedAt)
+
+	if err != nil {
+		return models.Supplier{}, err
+	}
+
+	return supplier, nil
+}
+
+// ===== UPDATE =====
+func (r *SupplierRepository) Update(id int, updated models.Supplier) (models.Supplier, error) {
+	err := r.db.QueryRow(`
+		UPDATE suppliers
+		SET name = $1, phone = $2, email = $3, address = $4
+		WHERE id = $5
+		RETURNING id, created_at
+	`,
+		updated.Name,
+		updated.Phone,
+		updated.Email,
+		updated.Address,
+		id,
+	).Scan(&updated.ID, &updated.CreatedAt)
+
+	if err != nil {
+		return models.Supplier{}, err
+	}
+
+	return updated, nil
+}
+
+// ===== DELETE =====
+// Delete menolak menghapus supplier yang sudah punya purchase order
+func (r *SupplierRepository) Delete(id int) error {
+	var inUse bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM purchase_orders WHERE supplier_id = $1)
+	`, id).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrSupplierInUse
+	}
+
+	result, err := r.db.Exec(`
+		DELETE FROM suppliers
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
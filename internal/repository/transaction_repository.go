@@ -2,11 +2,62 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"kasir-api/internal/models"
+	"math"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTransactionVoided   = errors.New("transaction already voided")
+	ErrInsufficientPayment = errors.New("payment does not cover total amount")
+	ErrNonCashOverpayment  = errors.New("only cash payments can produce change")
 )
 
+// kolom yang dibaca oleh scanTransaction, urutannya harus sama
+const transactionColumns = `t.id, COALESCE(t.invoice_number, ''), t.gross_amount, t.discount_amount, t.cart_discount_amount,
+	t.voucher_id, t.voucher_discount_amount, t.customer_ref,
+	t.service_charge_rate, t.service_charge_amount, t.tax_rate, t.tax_inclusive, t.taxable_amount, t.tax_amount,
+	t.total_amount, t.paid_amount, t.change_amount, t.status, t.voided_by, t.void_reason, t.voided_at, t.created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransaction(row rowScanner) (models.Transaction, error) {
+	var t models.Transaction
+	err := row.Scan(
+		&t.ID,
+		&t.InvoiceNumber,
+		&t.GrossAmount,
+		&t.DiscountAmount,
+		&t.CartDiscountAmount,
+		&t.VoucherID,
+		&t.VoucherDiscountAmount,
+		&t.CustomerRef,
+		&t.ServiceChargeRate,
+		&t.ServiceChargeAmount,
+		&t.TaxRate,
+		&t.TaxInclusive,
+		&t.TaxableAmount,
+		&t.TaxAmount,
+		&t.TotalAmount,
+		&t.PaidAmount,
+		&t.ChangeAmount,
+		&t.Status,
+		&t.VoidedBy,
+		&t.VoidReason,
+		&t.VoidedAt,
+		&t.CreatedAt,
+	)
+	return t, err
+}
+
 type TransactionRepository struct {
 	db *sql.DB
 }
@@ -17,102 +68,1008 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	}
 }
 
-func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
+// LineAdjuster dipanggil setelah harga dan stok terkunci, sebelum diskon manual,
+// untuk menerapkan potongan otomatis (promo) pada baris detail
+type LineAdjuster func(details []models.TransactionDetail)
+
+// CartNormalizer memvalidasi dan merapikan keranjang yang dibaca repository
+// di dalam DB transaction checkout (resume held cart)
+type CartNormalizer func(req models.CheckoutRequest) (models.CheckoutRequest, error)
+
+// CheckoutOptions berisi aturan harga yang disiapkan service untuk satu checkout
+type CheckoutOptions struct {
+	AdjustLines   LineAdjuster
+	NormalizeCart CartNormalizer
+	Tax           models.TaxSettings
+	Invoice       models.InvoiceSettings
+}
+
+func (repo *TransactionRepository) CreateTransaction(req models.CheckoutRequest, opts CheckoutOptions) (*models.Transaction, error) {
 	tx, err := repo.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	totalAmount := 0
-	details := make([]models.TransactionDetail, 0)
+	// held cart dikunci lebih dulu supaya tidak bisa di-resume dua kali, isi keranjang
+	// dibaca setelah lock supaya update held cart yang bersamaan tidak terlewat
+	if req.HeldCartID > 0 {
+		if err := lockHeldCart(tx, req.HeldCartID); err != nil {
+			return nil, err
+		}
+
+		req, err = loadHeldCartCheckout(tx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.NormalizeCart != nil {
+			req, err = opts.NormalizeCart(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// baris detail disimpan urut product ID, sama dengan urutan lock di loadCartProducts
+	items := make([]models.CheckoutItem, len(req.Items))
+	copy(items, req.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ProductID < items[j].ProductID
+	})
+	req.Items = items
 
-	stmtDetail, err := tx.Prepare(`
-	INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal)
-	VALUES ($1, $2, $3, $4)
-	RETURNING id
-`)
+	products, err := loadCartProducts(tx, req.Items, req.HeldCartID, true)
 	if err != nil {
 		return nil, err
 	}
-	defer stmtDetail.Close()
 
-	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+	// semua baris diperiksa dulu supaya client mendapat daftar lengkap baris yang gagal
+	requested := make(map[int]int)
+	for _, item := range req.Items {
+		requested[item.ProductID] += item.Quantity
+	}
 
-		err := tx.QueryRow(`
-			SELECT name, price, stock
-			FROM products
-			WHERE id = $1
-			FOR UPDATE
-		`, item.ProductID).Scan(&productName, &productPrice, &stock)
+	details := make([]models.TransactionDetail, 0, len(req.Items))
+	var failures []models.CheckoutItemError
 
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
-		}
-		if err != nil {
-			return nil, err
+	for _, item := range req.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			failures = append(failures, models.CheckoutItemError{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Reason:    models.CheckoutErrorProductNotFound,
+			})
+			continue
 		}
 
-		if stock < item.Quantity {
-			return nil, fmt.Errorf("stock product %s not enough", productName)
+		// stok yang direservasi held cart lain tidak bisa dijual
+		if available := product.stock - product.reserved; available < requested[item.ProductID] {
+			failures = append(failures, models.CheckoutItemError{
+				ProductID:   item.ProductID,
+				ProductName: product.name,
+				Quantity:    item.Quantity,
+				Available:   available,
+				Reason:      models.CheckoutErrorInsufficientStock,
+			})
+			continue
 		}
 
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
-
-		_, err = tx.Exec(`
-			UPDATE products
-			SET stock = stock - $1
-			WHERE id = $2
-		`, item.Quantity, item.ProductID)
-		if err != nil {
-			return nil, err
-		}
+		gross := product.price * item.Quantity
 
+		// nama, harga dan harga pokok di-snapshot supaya histori dan laporan
+		// tidak berubah saat product diubah
 		details = append(details, models.TransactionDetail{
 			ProductID:   item.ProductID,
-			ProductName: productName,
+			ProductName: product.name,
 			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+			UnitPrice:   product.price,
+			UnitCost:    product.costPrice,
+			GrossAmount: gross,
+			Subtotal:    gross,
+			TaxClass:    product.taxClass,
 		})
 	}
 
+	if len(failures) > 0 {
+		return nil, &CheckoutError{Items: failures}
+	}
+
+	var applyVoucher voucherApplier
+	if req.VoucherCode != "" {
+		applyVoucher = func(amount int) (*models.Voucher, int, error) {
+			return redeemVoucher(tx, req.VoucherCode, req.CustomerRef, amount, time.Now())
+		}
+	}
+
+	totals, err := priceCart(details, req.Items, req.Discount, opts, applyVoucher)
+	if err != nil {
+		return nil, err
+	}
+	totalAmount := totals.totalAmount
+
+	paidAmount := 0
+	cashAmount := 0
+	for _, p := range req.Payments {
+		paidAmount += p.Amount
+		if p.Method == models.PaymentMethodCash {
+			cashAmount += p.Amount
+		}
+	}
+
+	if paidAmount < totalAmount {
+		return nil, fmt.Errorf("%w: total %d, paid %d", ErrInsufficientPayment, totalAmount, paidAmount)
+	}
+
+	// kembalian hanya boleh berasal dari pembayaran cash
+	changeAmount := paidAmount - totalAmount
+	if changeAmount > cashAmount {
+		return nil, fmt.Errorf("%w: non-cash payments exceed total by %d", ErrNonCashOverpayment, changeAmount-cashAmount)
+	}
+
+	invoiceNumber, err := nextInvoiceNumber(tx, opts.Invoice, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	var transactionID int
 	var createdAt time.Time
 	err = tx.QueryRow(`
-	INSERT INTO transactions (total_amount)
-	VALUES ($1)
+	INSERT INTO transactions (invoice_number, gross_amount, discount_amount, cart_discount_amount, voucher_id, voucher_discount_amount,
+		customer_ref, service_charge_rate, service_charge_amount, tax_rate, tax_inclusive, taxable_amount, tax_amount,
+		total_amount, paid_amount, change_amount)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	RETURNING id, created_at
-`, totalAmount).Scan(&transactionID, &createdAt)
+`,
+		invoiceNumber,
+		totals.grossAmount,
+		totals.discountAmount,
+		totals.cartDiscount,
+		totals.voucherID,
+		totals.voucherDiscount,
+		req.CustomerRef,
+		opts.Tax.ServiceChargeRate,
+		totals.serviceCharge,
+		opts.Tax.Rate,
+		opts.Tax.Inclusive,
+		totals.taxableAmount,
+		totals.taxAmount,
+		totalAmount,
+		paidAmount,
+		changeAmount,
+	).Scan(&transactionID, &createdAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertTransactionDetails(tx, transactionID, details); err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int, len(details))
+	deltas := make([]int, len(details))
+	for i, d := range details {
+		productIDs[i] = d.ProductID
+		deltas[i] = -d.Quantity
+	}
+
+	err = moveStock(tx, productIDs, deltas, models.StockMovement{
+		Reason:        models.StockReasonSale,
+		ReferenceType: models.StockReferenceTransaction,
+		ReferenceID:   &transactionID,
+		Note:          invoiceNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.HeldCartID > 0 {
+		if err := markHeldCartResumed(tx, req.HeldCartID, transactionID); err != nil {
+			return nil, err
+		}
+	}
+
+	payments, err := insertTransactionPayments(tx, transactionID, req.Payments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.Transaction{
+		ID:                    transactionID,
+		InvoiceNumber:         invoiceNumber,
+		GrossAmount:           totals.grossAmount,
+		DiscountAmount:        totals.discountAmount,
+		CartDiscountAmount:    totals.cartDiscount,
+		VoucherID:             totals.voucherID,
+		VoucherDiscountAmount: totals.voucherDiscount,
+		CustomerRef:           req.CustomerRef,
+		ServiceChargeRate:     opts.Tax.ServiceChargeRate,
+		ServiceChargeAmount:   totals.serviceCharge,
+		TaxRate:               opts.Tax.Rate,
+		TaxInclusive:          opts.Tax.Inclusive,
+		TaxableAmount:         totals.taxableAmount,
+		TaxAmount:             totals.taxAmount,
+		TotalAmount:           totalAmount,
+		PaidAmount:            paidAmount,
+		ChangeAmount:          changeAmount,
+		Status:                models.TransactionStatusCompleted,
+		CreatedAt:             createdAt,
+		Details:               details,
+		Payments:              payments,
+	}, nil
+}
+
+// QuoteTransaction menghitung harga keranjang dengan aturan yang sama seperti
+// CreateTransaction tanpa lock dan tanpa menulis apa pun. Baris yang product-nya
+// tidak ada atau stoknya kurang dicatat di Failures, bukan menggagalkan quote.
+func (repo *TransactionRepository) QuoteTransaction(req models.CheckoutRequest, opts CheckoutOptions) (*models.CheckoutQuote, error) {
+	quote := models.CheckoutQuote{
+		Lines:    make([]models.QuoteLine, 0, len(req.Items)),
+		Failures: make([]models.CheckoutItemError, 0),
+	}
+
+	items := make([]models.CheckoutItem, 0, len(req.Items))
+	details := make([]models.TransactionDetail, 0, len(req.Items))
+
+	products, err := loadCartProducts(repo.db, req.Items, req.HeldCartID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range req.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			quote.Failures = append(quote.Failures, models.CheckoutItemError{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Reason:    models.CheckoutErrorProductNotFound,
+			})
+			continue
+		}
+
+		// baris dengan stok kurang tetap dihitung supaya total tetap terlihat
+		if available := product.stock - product.reserved; available < item.Quantity {
+			quote.Failures = append(quote.Failures, models.CheckoutItemError{
+				ProductID:   item.ProductID,
+				ProductName: product.name,
+				Quantity:    item.Quantity,
+				Available:   available,
+				Reason:      models.CheckoutErrorInsufficientStock,
+			})
+		}
+
+		gross := product.price * item.Quantity
+
+		items = append(items, item)
+		details = append(details, models.TransactionDetail{
+			ProductID:   item.ProductID,
+			ProductName: product.name,
+			Quantity:    item.Quantity,
+			UnitPrice:   product.price,
+			GrossAmount: gross,
+			Subtotal:    gross,
+			TaxClass:    product.taxClass,
+		})
+	}
+
+	// voucher yang ditolak tidak menggagalkan quote, keranjang dihitung tanpa voucher
+	var applyVoucher voucherApplier
+	if req.VoucherCode != "" {
+		applyVoucher = func(amount int) (*models.Voucher, int, error) {
+			voucher, discount, err := checkVoucher(repo.db, req.VoucherCode, req.CustomerRef, amount, time.Now(), "")
+			if IsVoucherError(err) {
+				quote.VoucherError = err.Error()
+				return nil, 0, nil
+			}
+			return voucher, discount, err
+		}
+	}
+
+	totals, err := priceCart(details, items, req.Discount, opts, applyVoucher)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range details {
+		quote.Lines = append(quote.Lines, models.QuoteLine{
+			ProductID:           d.ProductID,
+			ProductName:         d.ProductName,
+			Quantity:            d.Quantity,
+			UnitPrice:           d.UnitPrice,
+			PromotionID:         d.PromotionID,
+			GrossAmount:         d.GrossAmount,
+			DiscountAmount:      d.DiscountAmount,
+			Subtotal:            d.Subtotal,
+			TaxClass:            d.TaxClass,
+			ServiceChargeAmount: d.ServiceChargeAmount,
+			TaxAmount:           d.TaxAmount,
+		})
+	}
+
+	quote.Valid = len(quote.Failures) == 0 && quote.VoucherError == ""
+	quote.GrossAmount = totals.grossAmount
+	quote.DiscountAmount = totals.discountAmount
+	quote.CartDiscountAmount = totals.cartDiscount
+	quote.VoucherDiscountAmount = totals.voucherDiscount
+	quote.ServiceChargeAmount = totals.serviceCharge
+	quote.TaxableAmount = totals.taxableAmount
+	quote.TaxAmount = totals.taxAmount
+	quote.TotalAmount = totals.totalAmount
+
+	return &quote, nil
+}
+
+// cartProduct adalah data product yang dibutuhkan untuk menghitung harga keranjang
+type cartProduct struct {
+	name      string
+	price     int
+	costPrice int
+	stock     int
+	reserved  int
+	taxClass  string
+}
+
+// loadCartProducts mengambil semua product keranjang dalam satu query beserta stok
+// yang direservasi held cart lain (kecuali excludeCartID). Jika lock true, baris product
+// dikunci FOR UPDATE dengan urutan ID supaya checkout paralel tidak saling deadlock.
+func loadCartProducts(q queryer, items []models.CheckoutItem, excludeCartID int, lock bool) (map[int]cartProduct, error) {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	lockClause := ""
+	if lock {
+		lockClause = "FOR UPDATE"
+	}
+
+	rows, err := q.Query(`
+		SELECT id, name, price, cost_price, stock, tax_class
+		FROM products
+		WHERE id = ANY($1)
+		ORDER BY id
+		`+lockClause, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[int]cartProduct)
+	for rows.Next() {
+		var id int
+		var p cartProduct
+		if err := rows.Scan(&id, &p.name, &p.price, &p.costPrice, &p.stock, &p.taxClass); err != nil {
+			return nil, err
+		}
+		products[id] = p
+	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// reservasi dibaca di statement terpisah setelah lock: subquery di statement
+	// FOR UPDATE memakai snapshot sebelum menunggu lock, sehingga reservasi dari
+	// hold cart yang baru commit tidak terlihat
+	reserved, err := reservedStocks(q, ids, excludeCartID)
 	if err != nil {
 		return nil, err
 	}
 
+	for id, qty := range reserved {
+		if p, ok := products[id]; ok {
+			p.reserved = qty
+			products[id] = p
+		}
+	}
+
+	return products, nil
+}
+
+// insertTransactionDetails menyimpan semua baris detail dengan satu INSERT.
+// Urutan RETURNING dan nilai serial tidak dijamin mengikuti urutan unnest, jadi id
+// dipetakan kembali lewat product_id (unik per transaksi setelah normalizeCart).
+func insertTransactionDetails(tx *sql.Tx, transactionID int, details []models.TransactionDetail) error {
+	n := len(details)
+	productIDs := make([]int, n)
+	productNames := make([]string, n)
+	quantities := make([]int, n)
+	unitPrices := make([]int, n)
+	unitCosts := make([]int, n)
+	promotionIDs := make([]sql.NullInt64, n)
+	grossAmounts := make([]int, n)
+	discountAmounts := make([]int, n)
+	subtotals := make([]int, n)
+	taxClasses := make([]string, n)
+	serviceCharges := make([]int, n)
+	taxAmounts := make([]int, n)
+
+	for i, d := range details {
+		productIDs[i] = d.ProductID
+		productNames[i] = d.ProductName
+		quantities[i] = d.Quantity
+		unitPrices[i] = d.UnitPrice
+		unitCosts[i] = d.UnitCost
+		if d.PromotionID != nil {
+			promotionIDs[i] = sql.NullInt64{Int64: int64(*d.PromotionID), Valid: true}
+		}
+		grossAmounts[i] = d.GrossAmount
+		discountAmounts[i] = d.DiscountAmount
+		subtotals[i] = d.Subtotal
+		taxClasses[i] = d.TaxClass
+		serviceCharges[i] = d.ServiceChargeAmount
+		taxAmounts[i] = d.TaxAmount
+	}
+
+	rows, err := tx.Query(`
+		INSERT INTO transaction_details (transaction_id, product_id, product_name, quantity, unit_price, unit_cost,
+			promotion_id, gross_amount, discount_amount, subtotal, tax_class, service_charge_amount, tax_amount)
+		SELECT $1, d.product_id, d.product_name, d.quantity, d.unit_price, d.unit_cost,
+			d.promotion_id, d.gross_amount, d.discount_amount, d.subtotal, d.tax_class, d.service_charge_amount, d.tax_amount
+		FROM unnest($2::int[], $3::varchar[], $4::int[], $5::int[], $6::int[], $7::int[], $8::int[], $9::int[], $10::int[],
+			$11::varchar[], $12::int[], $13::int[])
+			WITH ORDINALITY AS d(product_id, product_name, quantity, unit_price, unit_cost, promotion_id, gross_amount,
+				discount_amount, subtotal, tax_class, service_charge_amount, tax_amount, ord)
+		ORDER BY d.ord
+		RETURNING id, product_id
+	`,
+		transactionID,
+		pq.Array(productIDs),
+		pq.Array(productNames),
+		pq.Array(quantities),
+		pq.Array(unitPrices),
+		pq.Array(unitCosts),
+		pq.Array(promotionIDs),
+		pq.Array(grossAmounts),
+		pq.Array(discountAmounts),
+		pq.Array(subtotals),
+		pq.Array(taxClasses),
+		pq.Array(serviceCharges),
+		pq.Array(taxAmounts),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	ids := make(map[int]int, n)
+	for rows.Next() {
+		var id, productID int
+		if err := rows.Scan(&id, &productID); err != nil {
+			return err
+		}
+		ids[productID] = id
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
 	for i := range details {
 		details[i].TransactionID = transactionID
+		details[i].ID = ids[details[i].ProductID]
+	}
+
+	return nil
+}
+
+// insertTransactionPayments menyimpan semua pembayaran dengan satu INSERT. Id dipetakan
+// kembali lewat method dan amount; pembayaran yang method dan amount-nya sama identik,
+// jadi urutan di antara mereka tidak berpengaruh.
+func insertTransactionPayments(tx *sql.Tx, transactionID int, reqPayments []models.CheckoutPayment) ([]models.TransactionPayment, error) {
+	methods := make([]string, len(reqPayments))
+	amounts := make([]int, len(reqPayments))
+	for i, p := range reqPayments {
+		methods[i] = p.Method
+		amounts[i] = p.Amount
+	}
+
+	rows, err := tx.Query(`
+		INSERT INTO transaction_payments (transaction_id, method, amount)
+		SELECT $1, p.method, p.amount
+		FROM unnest($2::varchar[], $3::int[]) WITH ORDINALITY AS p(method, amount, ord)
+		ORDER BY p.ord
+		RETURNING id, method, amount
+	`, transactionID, pq.Array(methods), pq.Array(amounts))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[models.CheckoutPayment][]int)
+	for rows.Next() {
+		var id int
+		var p models.CheckoutPayment
+		if err := rows.Scan(&id, &p.Method, &p.Amount); err != nil {
+			return nil, err
+		}
+		ids[p] = append(ids[p], id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	payments := make([]models.TransactionPayment, len(reqPayments))
+	for i, p := range reqPayments {
+		id := ids[p][0]
+		ids[p] = ids[p][1:]
+		payments[i] = models.TransactionPayment{
+			ID:            id,
+			TransactionID: transactionID,
+			Method:        p.Method,
+			Amount:        p.Amount,
+		}
+	}
+
+	return payments, nil
+}
+
+// cartTotals adalah hasil perhitungan harga satu keranjang oleh priceCart
+type cartTotals struct {
+	grossAmount     int
+	discountAmount  int
+	cartDiscount    int
+	voucherID       *int
+	voucherDiscount int
+	serviceCharge   int
+	taxableAmount   int
+	taxAmount       int
+	totalAmount     int
+}
+
+// voucherApplier mengembalikan voucher dan potongannya untuk total setelah diskon keranjang,
+// voucher nil berarti tidak ada potongan
+type voucherApplier func(amount int) (*models.Voucher, int, error)
 
-		err = stmtDetail.QueryRow(
-			transactionID,
-			details[i].ProductID,
-			details[i].Quantity,
-			details[i].Subtotal,
-		).Scan(&details[i].ID)
+// priceCart menghitung harga keranjang dari details yang sudah berisi harga gross
+// (sejajar dengan items): promo, diskon manual per item, diskon keranjang, voucher,
+// lalu service charge dan pajak. Dipakai checkout dan quote supaya hasilnya selalu sama.
+func priceCart(details []models.TransactionDetail, items []models.CheckoutItem, cartDiscountReq *models.Discount,
+	opts CheckoutOptions, applyVoucher voucherApplier) (cartTotals, error) {
+	var totals cartTotals
 
+	for _, d := range details {
+		totals.grossAmount += d.GrossAmount
+	}
+
+	if opts.AdjustLines != nil {
+		opts.AdjustLines(details)
+	}
+
+	// diskon manual per item dihitung dari harga setelah promo
+	lineNetAmount := 0
+	for i, item := range items {
+		discount := item.Discount.Amount(details[i].Subtotal)
+		details[i].DiscountAmount += discount
+		details[i].Subtotal -= discount
+		lineNetAmount += details[i].Subtotal
+	}
+
+	// diskon keranjang dibagi proporsional ke tiap baris supaya subtotal
+	// per item tetap nett (dipakai refund dan report)
+	totals.cartDiscount = cartDiscountReq.Amount(lineNetAmount)
+	allocateDiscount(details, totals.cartDiscount)
+
+	totals.totalAmount = lineNetAmount - totals.cartDiscount
+
+	// voucher dihitung terakhir dari total setelah diskon keranjang
+	if applyVoucher != nil {
+		voucher, discount, err := applyVoucher(totals.totalAmount)
+		if err != nil {
+			return totals, err
+		}
+
+		if voucher != nil {
+			totals.voucherID = &voucher.ID
+			totals.voucherDiscount = discount
+			allocateDiscount(details, totals.voucherDiscount)
+			totals.totalAmount -= totals.voucherDiscount
+		}
+	}
+
+	totals.discountAmount = totals.grossAmount - totals.totalAmount
+
+	totals.serviceCharge, totals.taxableAmount, totals.taxAmount = applyTaxAndService(details, opts.Tax)
+	totals.totalAmount += totals.serviceCharge
+	if !opts.Tax.Inclusive {
+		totals.totalAmount += totals.taxAmount
+	}
+
+	return totals, nil
+}
+
+// nextInvoiceNumber menaikkan counter scope invoice di dalam tx. Row counter
+// terkunci sampai tx commit / rollback, sehingga checkout paralel antri dan
+// checkout yang gagal tidak menghabiskan nomor (tidak ada nomor yang loncat).
+func nextInvoiceNumber(tx *sql.Tx, settings models.InvoiceSettings, now time.Time) (string, error) {
+	scope := settings.Scope(now)
+
+	var number int
+	err := tx.QueryRow(`
+		INSERT INTO invoice_sequences (scope, last_number)
+		VALUES ($1, 1)
+		ON CONFLICT (scope) DO UPDATE
+		SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`, scope).Scan(&number)
+	if err != nil {
+		return "", err
+	}
+
+	return settings.Format(scope, number), nil
+}
+
+// allocateDiscount membagi discount ke details sesuai porsi subtotal
+func allocateDiscount(details []models.TransactionDetail, discount int) {
+	weights := make([]int, len(details))
+	for i := range details {
+		weights[i] = details[i].Subtotal
+	}
+
+	for i, share := range allocateProportional(discount, weights) {
+		details[i].DiscountAmount += share
+		details[i].Subtotal -= share
+	}
+}
+
+// allocateProportional membagi total sesuai bobot. Pembulatan dihitung
+// kumulatif supaya jumlahnya pas dan tiap bagian tidak melebihi bobotnya
+// (selama total <= jumlah bobot).
+func allocateProportional(total int, weights []int) []int {
+	shares := make([]int, len(weights))
+	if total <= 0 {
+		return shares
+	}
+
+	base := 0
+	for _, w := range weights {
+		base += w
+	}
+	if base == 0 {
+		return shares
+	}
+
+	cumulative := 0
+	allocated := 0
+	for i, w := range weights {
+		cumulative += w
+		shares[i] = total*cumulative/base - allocated
+		allocated += shares[i]
+	}
+
+	return shares
+}
+
+// applyTaxAndService menghitung service charge dari total nett lalu pajak.
+// Service charge ikut kena pajak untuk baris taxable. Untuk harga inclusive,
+// pajak diambil dari dalam harga (tidak menambah total).
+func applyTaxAndService(details []models.TransactionDetail, settings models.TaxSettings) (serviceCharge int, taxableAmount int, taxAmount int) {
+	netAmount := 0
+	subtotals := make([]int, len(details))
+	for i := range details {
+		subtotals[i] = details[i].Subtotal
+		netAmount += details[i].Subtotal
+	}
+
+	serviceCharge = int(math.Round(float64(netAmount) * settings.ServiceChargeRate / 100))
+	serviceShares := allocateProportional(serviceCharge, subtotals)
+
+	bases := make([]int, len(details))
+	for i := range details {
+		details[i].ServiceChargeAmount = serviceShares[i]
+		if details[i].TaxClass != models.TaxClassExempt {
+			bases[i] = details[i].Subtotal + serviceShares[i]
+			taxableAmount += bases[i]
+		}
+	}
+
+	if settings.Rate > 0 {
+		if settings.Inclusive {
+			taxAmount = int(math.Round(float64(taxableAmount) * settings.Rate / (100 + settings.Rate)))
+		} else {
+			taxAmount = int(math.Round(float64(taxableAmount) * settings.Rate / 100))
+		}
+	}
+
+	for i, share := range allocateProportional(taxAmount, bases) {
+		details[i].TaxAmount = share
+	}
+
+	return serviceCharge, taxableAmount, taxAmount
+}
+
+func (repo *TransactionRepository) GetAll(filter models.TransactionFilter) ([]models.Transaction, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.StartDate != nil {
+		args = append(args, *filter.StartDate)
+		conditions = append(conditions, fmt.Sprintf("t.created_at >= $%d", len(args)))
+	}
+
+	if filter.EndDate != nil {
+		args = append(args, *filter.EndDate)
+		conditions = append(conditions, fmt.Sprintf("t.created_at < $%d", len(args)))
+	}
+
+	if filter.MinAmount > 0 {
+		args = append(args, filter.MinAmount)
+		conditions = append(conditions, fmt.Sprintf("t.total_amount >= $%d", len(args)))
+	}
+
+	if filter.MaxAmount > 0 {
+		args = append(args, filter.MaxAmount)
+		conditions = append(conditions, fmt.Sprintf("t.total_amount <= $%d", len(args)))
+	}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+
+	if filter.Invoice != "" {
+		args = append(args, "%"+filter.Invoice+"%")
+		conditions = append(conditions, fmt.Sprintf("t.invoice_number ILIKE $%d", len(args)))
+	}
+
+	if filter.ProductID > 0 {
+		args = append(args, filter.ProductID)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM transaction_details td
+			WHERE td.transaction_id = t.id AND td.product_id = $%d
+		)`, len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM transactions t"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transactions t%s
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $%d OFFSET $%d
+	`, transactionColumns, where, len(args)-1, len(args))
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.Transaction, 0)
+	var ids []int
+
+	for rows.Next() {
+		t, err := scanTransaction(rows)
 		if err != nil {
+			return nil, 0, err
+		}
+		transactions = append(transactions, t)
+		ids = append(ids, t.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	details, err := repo.getDetails(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	payments, err := repo.getPayments(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range transactions {
+		transactions[i].Details = details[transactions[i].ID]
+		transactions[i].Payments = payments[transactions[i].ID]
+	}
+
+	return transactions, total, nil
+}
+
+func (repo *TransactionRepository) GetByID(id int) (*models.Transaction, error) {
+	t, err := scanTransaction(repo.db.QueryRow(`
+		SELECT `+transactionColumns+`
+		FROM transactions t
+		WHERE t.id = $1
+	`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := repo.getDetails([]int{t.ID})
+	if err != nil {
+		return nil, err
+	}
+	t.Details = details[t.ID]
+
+	payments, err := repo.getPayments([]int{t.ID})
+	if err != nil {
+		return nil, err
+	}
+	t.Payments = payments[t.ID]
+
+	return &t, nil
+}
+
+// VoidTransaction membatalkan transaksi dan mengembalikan seluruh stok
+// yang belum dikembalikan lewat refund.
+func (repo *TransactionRepository) VoidTransaction(id int, req models.VoidRequest) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`
+		SELECT status
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.TransactionStatusVoided {
+		return nil, ErrTransactionVoided
+	}
+
+	// stok yang dikembalikan = qty terjual dikurangi qty yang sudah di-refund
+	rows, err := tx.Query(`
+		SELECT td.product_id, SUM(td.quantity - COALESCE(rd.qty, 0))
+		FROM transaction_details td
+		LEFT JOIN (
+			SELECT transaction_detail_id, SUM(quantity) AS qty
+			FROM refund_details
+			GROUP BY transaction_detail_id
+		) rd ON rd.transaction_detail_id = td.id
+		WHERE td.transaction_id = $1
+		GROUP BY td.product_id
+		ORDER BY td.product_id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var productIDs, deltas []int
+	for rows.Next() {
+		var productID, qty int
+		if err := rows.Scan(&productID, &qty); err != nil {
+			rows.Close()
 			return nil, err
 		}
+		productIDs = append(productIDs, productID)
+		deltas = append(deltas, qty)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	err = moveStock(tx, productIDs, deltas, models.StockMovement{
+		Reason:        models.StockReasonVoid,
+		ReferenceType: models.StockReferenceTransaction,
+		ReferenceID:   &id,
+		CreatedBy:     req.VoidedBy,
+		Note:          req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE transactions
+		SET status = $1, voided_by = $2, void_reason = $3, voided_at = NOW()
+		WHERE id = $4
+	`, models.TransactionStatusVoided, req.VoidedBy, req.Reason, id)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
-	}, nil
+	return repo.GetByID(id)
+}
+
+// getDetails mengambil detail beberapa transaksi sekaligus, dikelompokkan per transaction_id
+func (repo *TransactionRepository) getDetails(transactionIDs []int) (map[int][]models.TransactionDetail, error) {
+	result := make(map[int][]models.TransactionDetail)
+	if len(transactionIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := repo.db.Query(`
+		SELECT td.id, td.transaction_id, td.product_id, td.product_name, td.quantity, td.unit_price, td.promotion_id,
+			td.gross_amount, td.discount_amount, td.subtotal, td.tax_class, td.service_charge_amount, td.tax_amount
+		FROM transaction_details td
+		WHERE td.transaction_id = ANY($1)
+		ORDER BY td.id
+	`, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.TransactionDetail
+		if err := rows.Scan(
+			&d.ID,
+			&d.TransactionID,
+			&d.ProductID,
+			&d.ProductName,
+			&d.Quantity,
+			&d.UnitPrice,
+			&d.PromotionID,
+			&d.GrossAmount,
+			&d.DiscountAmount,
+			&d.Subtotal,
+			&d.TaxClass,
+			&d.ServiceChargeAmount,
+			&d.TaxAmount,
+		); err != nil {
+			return nil, err
+		}
+		result[d.TransactionID] = append(result[d.TransactionID], d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getPayments mengambil pembayaran beberapa transaksi sekaligus, dikelompokkan per transaction_id
+func (repo *TransactionRepository) getPayments(transactionIDs []int) (map[int][]models.TransactionPayment, error) {
+	result := make(map[int][]models.TransactionPayment)
+	if len(transactionIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := repo.db.Query(`
+		SELECT id, transaction_id, method, amount
+		FROM transaction_payments
+		WHERE transaction_id = ANY($1)
+		ORDER BY id
+	`, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.TransactionPayment
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount); err != nil {
+			return nil, err
+		}
+		result[p.TransactionID] = append(result[p.TransactionID], p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
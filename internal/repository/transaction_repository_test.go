@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"kasir-api/internal/models"
+)
+
+func TestAllocateProportional(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		weights []int
+		want    []int
+	}{
+		{"even split", 90, []int{100, 100, 100}, []int{30, 30, 30}},
+		{"remainder goes to later lines", 100, []int{1, 1, 1}, []int{33, 33, 34}},
+		{"single unit remainder", 1, []int{1, 1, 1}, []int{0, 0, 1}},
+		{"zero weight gets nothing", 10, []int{3, 0, 7}, []int{3, 0, 7}},
+		{"uneven weights", 1000, []int{3333, 3333, 3334}, []int{333, 333, 334}},
+		{"zero total", 0, []int{5, 5}, []int{0, 0}},
+		{"negative total", -10, []int{5, 5}, []int{0, 0}},
+		{"zero weights", 10, []int{0, 0}, []int{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocateProportional(tt.total, tt.weights)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("allocateProportional(%d, %v) = %v, want %v", tt.total, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	details := []models.TransactionDetail{
+		{Subtotal: 3333, DiscountAmount: 100},
+		{Subtotal: 3333},
+		{Subtotal: 3334},
+	}
+
+	allocateDiscount(details, 1000)
+
+	wantDiscount := []int{433, 333, 334}
+	wantSubtotal := []int{3000, 3000, 3000}
+	for i, d := range details {
+		if d.DiscountAmount != wantDiscount[i] || d.Subtotal != wantSubtotal[i] {
+			t.Errorf("line %d: discount %d subtotal %d, want discount %d subtotal %d",
+				i, d.DiscountAmount, d.Subtotal, wantDiscount[i], wantSubtotal[i])
+		}
+	}
+}
+
+func TestPriceCart(t *testing.T) {
+	voucher := func(id, discount int) voucherApplier {
+		return func(amount int) (*models.Voucher, int, error) {
+			return &models.Voucher{ID: id}, discount, nil
+		}
+	}
+
+	tests := []struct {
+		name         string
+		gross        []int
+		items        []models.CheckoutItem
+		cartDiscount *models.Discount
+		tax          models.TaxSettings
+		applyVoucher voucherApplier
+		wantSubtotal []int
+		wantDiscount []int
+		wantTotals   cartTotals
+	}{
+		{
+			name:         "no discount",
+			gross:        []int{10000, 5000},
+			items:        []models.CheckoutItem{{}, {}},
+			wantSubtotal: []int{10000, 5000},
+			wantDiscount: []int{0, 0},
+			wantTotals:   cartTotals{grossAmount: 15000, taxableAmount: 15000, totalAmount: 15000},
+		},
+		{
+			name:  "line and cart discount",
+			gross: []int{10000, 5000},
+			items: []models.CheckoutItem{
+				{Discount: &models.Discount{Type: models.DiscountTypeFixed, Value: 1000}},
+				{},
+			},
+			cartDiscount: &models.Discount{Type: models.DiscountTypePercentage, Value: 10},
+			wantSubtotal: []int{8100, 4500},
+			wantDiscount: []int{1900, 500},
+			wantTotals: cartTotals{
+				grossAmount:    15000,
+				cartDiscount:   1400,
+				discountAmount: 2400,
+				taxableAmount:  12600,
+				totalAmount:    12600,
+			},
+		},
+		{
+			name:         "line discount capped at line amount",
+			gross:        []int{10000, 5000},
+			items:        []models.CheckoutItem{{Discount: &models.Discount{Type: models.DiscountTypeFixed, Value: 20000}}, {}},
+			wantSubtotal: []int{0, 5000},
+			wantDiscount: []int{10000, 0},
+			wantTotals:   cartTotals{grossAmount: 15000, discountAmount: 10000, taxableAmount: 5000, totalAmount: 5000},
+		},
+		{
+			name:         "cart discount remainder",
+			gross:        []int{100, 100, 100},
+			items:        []models.CheckoutItem{{}, {}, {}},
+			cartDiscount: &models.Discount{Type: models.DiscountTypeFixed, Value: 100},
+			wantSubtotal: []int{67, 67, 66},
+			wantDiscount: []int{33, 33, 34},
+			wantTotals:   cartTotals{grossAmount: 300, cartDiscount: 100, discountAmount: 100, taxableAmount: 200, totalAmount: 200},
+		},
+		{
+			name:         "voucher after cart discount with exclusive tax",
+			gross:        []int{6000, 4000},
+			items:        []models.CheckoutItem{{}, {}},
+			cartDiscount: &models.Discount{Type: models.DiscountTypeFixed, Value: 1000},
+			tax:          models.TaxSettings{Rate: 10},
+			applyVoucher: voucher(7, 900),
+			wantSubtotal: []int{4860, 3240},
+			wantDiscount: []int{1140, 760},
+			wantTotals: cartTotals{
+				grossAmount:     10000,
+				cartDiscount:    1000,
+				voucherDiscount: 900,
+				discountAmount:  1900,
+				taxableAmount:   8100,
+				taxAmount:       810,
+				totalAmount:     8910,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := make([]models.TransactionDetail, len(tt.gross))
+			for i, g := range tt.gross {
+				details[i] = models.TransactionDetail{Quantity: 1, GrossAmount: g, Subtotal: g}
+			}
+
+			got, err := priceCart(details, tt.items, tt.cartDiscount, CheckoutOptions{Tax: tt.tax}, tt.applyVoucher)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.wantTotals.voucherID = got.voucherID
+			if got != tt.wantTotals {
+				t.Errorf("totals = %+v, want %+v", got, tt.wantTotals)
+			}
+			if tt.applyVoucher != nil && (got.voucherID == nil || *got.voucherID != 7) {
+				t.Errorf("voucherID = %v, want 7", got.voucherID)
+			}
+
+			for i, d := range details {
+				if d.Subtotal != tt.wantSubtotal[i] || d.DiscountAmount != tt.wantDiscount[i] {
+					t.Errorf("line %d: subtotal %d discount %d, want subtotal %d discount %d",
+						i, d.Subtotal, d.DiscountAmount, tt.wantSubtotal[i], tt.wantDiscount[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPriceCartVoucherError(t *testing.T) {
+	details := []models.TransactionDetail{{Quantity: 1, GrossAmount: 1000, Subtotal: 1000}}
+	rejected := func(amount int) (*models.Voucher, int, error) {
+		return nil, 0, ErrVoucherMinPurchase
+	}
+
+	_, err := priceCart(details, []models.CheckoutItem{{}}, nil, CheckoutOptions{}, rejected)
+	if !errors.Is(err, ErrVoucherMinPurchase) {
+		t.Fatalf("err = %v, want %v", err, ErrVoucherMinPurchase)
+	}
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrVoucherNotFound         = errors.New("voucher code not found")
+	ErrVoucherInactive         = errors.New("voucher is inactive or expired")
+	ErrVoucherMinPurchase      = errors.New("purchase does not reach voucher minimum")
+	ErrVoucherUsageLimit       = errors.New("voucher usage limit reached")
+	ErrVoucherCustomerRequired = errors.New("customer_ref is required for this voucher")
+	ErrVoucherCustomerLimit    = errors.New("voucher usage limit for this customer reached")
+	ErrVoucherInUse            = errors.New("voucher already redeemed by transactions")
+	ErrVoucherCodeExists       = errors.New("voucher code already exists")
+)
+
+// IsVoucherError true jika err adalah penolakan voucher (bukan error database)
+func IsVoucherError(err error) bool {
+	for _, target := range []error{
+		ErrVoucherNotFound,
+		ErrVoucherInactive,
+		ErrVoucherMinPurchase,
+		ErrVoucherUsageLimit,
+		ErrVoucherCustomerRequired,
+		ErrVoucherCustomerLimit,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+type VoucherRepository struct {
+	db *sql.DB
+}
+
+func NewVoucherRepository(db *sql.DB) *VoucherRepository {
+	return &VoucherRepository{
+		db: db,
+	}
+}
+
+// used_count hanya menghitung transaksi yang tidak di-void
+const voucherColumns = `v.id, v.code, v.type, v.value, v.max_discount, v.min_purchase,
+	v.usage_limit, v.per_customer_limit, v.expires_at, v.is_active, v.created_at,
+	(SELECT COUNT(*) FROM transactions t WHERE t.voucher_id = v.id AND t.status <> '` + models.TransactionStatusVoided + `')`
+
+func scanVoucher(row rowScanner) (models.Voucher, error) {
+	var v models.Voucher
+	err := row.Scan(
+		&v.ID,
+		&v.Code,
+		&v.Type,
+		&v.Value,
+		&v.MaxDiscount,
+		&v.MinPurchase,
+		&v.UsageLimit,
+		&v.PerCustomerLimit,
+		&v.ExpiresAt,
+		&v.IsActive,
+		&v.CreatedAt,
+		&v.UsedCount,
+	)
+	return v, err
+}
+
+// ===== GET ALL =====
+func (r *VoucherRepository) GetAll() ([]models.Voucher, error) {
+	rows, err := r.db.Query(`SELECT ` + voucherColumns + ` FROM vouchers v ORDER BY v.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := make([]models.Voucher, 0)
+	for rows.Next() {
+		v, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// ===== GET BY ID =====
+func (r *VoucherRepository) GetByID(id int) (models.Voucher, error) {
+	return scanVoucher(r.db.QueryRow(`SELECT `+voucherColumns+` FROM vouchers v WHERE v.id = $1`, id))
+}
+
+// ===== CREATE =====
+func (r *VoucherRepository) Create(voucher models.Voucher) (models.Voucher, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO vouchers (code, type, value, max_discount, min_purchase,
+			usage_limit, per_customer_limit, expires_at, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`,
+		voucher.Code,
+		voucher.Type,
+		voucher.Value,
+		voucher.MaxDiscount,
+		voucher.MinPurchase,
+		voucher.UsageLimit,
+		voucher.PerCustomerLimit,
+		voucher.ExpiresAt,
+		voucher.IsActive,
+	).Scan(&voucher.ID, &voucher.CreatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Voucher{}, ErrVoucherCodeExists
+		}
+		return models.Voucher{}, err
+	}
+
+	return voucher, nil
+}
+
+// ===== UPDATE =====
+func (r *VoucherRepository) Update(id int, updated models.Voucher) (models.Voucher, error) {
+	err := r.db.QueryRow(`
+		UPDATE vouchers
+		SET code = $1, type = $2, value = $3, max_discount = $4, min_purchase = $5,
+			usage_limit = $6, per_customer_limit = $7, expires_at = $8, is_active = $9
+		WHERE id = $10
+		RETURNING id
+	`,
+		updated.Code,
+		updated.Type,
+		updated.Value,
+		updated.MaxDiscount,
+		updated.MinPurchase,
+		updated.UsageLimit,
+		updated.PerCustomerLimit,
+		updated.ExpiresAt,
+		updated.IsActive,
+		id,
+	).Scan(&updated.ID)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Voucher{}, ErrVoucherCodeExists
+		}
+		return models.Voucher{}, err
+	}
+
+	return r.GetByID(id)
+}
+
+// ===== DELETE =====
+func (r *VoucherRepository) Delete(id int) error {
+	var used bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM transactions WHERE voucher_id = $1)
+	`, id).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrVoucherInUse
+	}
+
+	result, err := r.db.Exec(`DELETE FROM vouchers WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ===== REDEMPTION REPORT =====
+func (r *VoucherRepository) GetRedemptionReport() ([]models.VoucherRedemptionReport, error) {
+	rows, err := r.db.Query(`
+		SELECT
+			v.id,
+			v.code,
+			COUNT(t.id),
+			COALESCE(SUM(t.voucher_discount_amount), 0)
+		FROM vouchers v
+		LEFT JOIN transactions t ON t.voucher_id = v.id AND t.status <> $1
+		GROUP BY v.id, v.code
+		ORDER BY COUNT(t.id) DESC, v.code
+	`, models.TransactionStatusVoided)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]models.VoucherRedemptionReport, 0)
+	for rows.Next() {
+		var rep models.VoucherRedemptionReport
+		if err := rows.Scan(&rep.VoucherID, &rep.Code, &rep.TotalRedemption, &rep.TotalDiscount); err != nil {
+			return nil, err
+		}
+		reports = append(reports, rep)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// redeemVoucher mengunci baris voucher (FOR UPDATE) di dalam transaksi checkout,
+// sehingga checkout paralel dengan kode yang sama antri dan limit tidak terlampaui.
+// Mengembalikan voucher dan potongan untuk nominal belanja amount.
+func redeemVoucher(tx *sql.Tx, code string, customerRef string, amount int, now time.Time) (*models.Voucher, int, error) {
+	return checkVoucher(tx, code, customerRef, amount, now, "FOR UPDATE OF v")
+}
+
+// queryRower dipenuhi *sql.DB dan *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// checkVoucher memvalidasi voucher untuk nominal belanja amount, lock kosong
+// berarti hanya membaca (dipakai quote)
+func checkVoucher(q queryRower, code string, customerRef string, amount int, now time.Time, lock string) (*models.Voucher, int, error) {
+	v, err := scanVoucher(q.QueryRow(`
+		SELECT `+voucherColumns+`
+		FROM vouchers v
+		WHERE v.code = $1
+		`+lock, code))
+	if err == sql.ErrNoRows {
+		return nil, 0, ErrVoucherNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !v.IsActive || (v.ExpiresAt != nil && !now.Before(*v.ExpiresAt)) {
+		return nil, 0, ErrVoucherInactive
+	}
+
+	if amount < v.MinPurchase {
+		return nil, 0, fmt.Errorf("%w: minimum %d", ErrVoucherMinPurchase, v.MinPurchase)
+	}
+
+	// used_count dihitung ulang di statement terpisah: subquery di statement FOR UPDATE
+	// memakai snapshot sebelum lock, sehingga pemakaian dari checkout yang baru commit
+	// tidak terlihat dan limit bisa terlampaui
+	err = q.QueryRow(`
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE voucher_id = $1 AND status <> $2
+	`, v.ID, models.TransactionStatusVoided).Scan(&v.UsedCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if v.UsageLimit > 0 && v.UsedCount >= v.UsageLimit {
+		return nil, 0, ErrVoucherUsageLimit
+	}
+
+	if v.PerCustomerLimit > 0 {
+		if customerRef == "" {
+			return nil, 0, ErrVoucherCustomerRequired
+		}
+
+		var customerUsed int
+		err = q.QueryRow(`
+			SELECT COUNT(*)
+			FROM transactions
+			WHERE voucher_id = $1 AND customer_ref = $2 AND status <> $3
+		`, v.ID, customerRef, models.TransactionStatusVoided).Scan(&customerUsed)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if customerUsed >= v.PerCustomerLimit {
+			return nil, 0, ErrVoucherCustomerLimit
+		}
+	}
+
+	return &v, v.DiscountFor(amount), nil
+}
@@ -3,32 +3,94 @@ package routes
 import (
 	"database/sql"
 	"net/http"
+	"strings"
 
+	"kasir-api/internal/config"
 	"kasir-api/internal/handlers"
+	"kasir-api/internal/models"
 	"kasir-api/internal/repository"
 	"kasir-api/internal/services"
 )
 
-func SetupRoutes(mux *http.ServeMux, db *sql.DB) {
+func SetupRoutes(mux *http.ServeMux, db *sql.DB, cfg config.Config) {
 
 	// ===== PRODUCT =====
 	productRepo := repository.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
+	categoryRepo := repository.NewCategoryRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	productService := services.NewProductService(productRepo, categoryRepo, supplierRepo)
 	productHandler := handlers.NewProductHandler(productService)
 
+	// ===== STOCK =====
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	stockService := services.NewStockService(stockMovementRepo)
+	stockHandler := handlers.NewStockHandler(stockService)
+
+	stockCountRepo := repository.NewStockCountRepository(db)
+	stockCountService := services.NewStockCountService(stockCountRepo)
+	stockCountHandler := handlers.NewStockCountHandler(stockCountService)
+
+	// ===== PURCHASING =====
+	supplierService := services.NewSupplierService(supplierRepo)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+
+	inventoryRepo := repository.NewInventoryRepository(db)
+	inventoryService := services.NewInventoryService(inventoryRepo)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+
 	// ===== CATEGORY =====
-	categoryRepo := repository.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepo)
+	categoryService := services.NewCategoryService(categoryRepo, productRepo)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
+	// ===== PROMOTION =====
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotionService := services.NewPromotionService(promotionRepo, productRepo)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+
+	// ===== VOUCHER =====
+	voucherRepo := repository.NewVoucherRepository(db)
+	voucherService := services.NewVoucherService(voucherRepo)
+	voucherHandler := handlers.NewVoucherHandler(voucherService)
+
 	// ===== TRANSACTIONS =====
 	transactionRepo := repository.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
+	transactionService := services.NewTransactionService(transactionRepo, promotionRepo, models.TaxSettings{
+		Rate:              cfg.TaxRate,
+		Inclusive:         cfg.TaxInclusive,
+		ServiceChargeRate: cfg.ServiceChargeRate,
+	}, models.InvoiceSettings{
+		Prefix:     cfg.InvoicePrefix,
+		OutletCode: cfg.OutletCode,
+		Reset:      cfg.InvoiceReset,
+	})
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 
+	heldCartRepo := repository.NewHeldCartRepository(db)
+	heldCartService := services.NewHeldCartService(heldCartRepo, transactionService, cfg.HeldCartTTL)
+	heldCartHandler := handlers.NewHeldCartHandler(heldCartService)
+
+	refundRepo := repository.NewRefundRepository(db)
+	refundService := services.NewRefundService(refundRepo)
+	refundHandler := handlers.NewRefundHandler(refundService)
+
+	receiptService := services.NewReceiptService(transactionRepo, models.StoreInfo{
+		Name:    cfg.StoreName,
+		Address: cfg.StoreAddress,
+		Phone:   cfg.StorePhone,
+		TaxID:   cfg.StoreTaxID,
+		Footer:  cfg.ReceiptFooter,
+	}, cfg.ReceiptWidth, cfg.ReceiptTemplate)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+
 	reportRepo := repository.NewReportRepository(db)
 	reportService := services.NewReportService(reportRepo)
 	reportHandler := handlers.GetTodaySalesReport(reportService)
+	taxReportHandler := handlers.GetTaxSummaryReport(reportService)
+	profitReportHandler := handlers.GetProfitReport(reportService)
 
 	// ===== PRODUCT ROUTES =====
 	mux.HandleFunc("/api/v1/products", func(w http.ResponseWriter, r *http.Request) {
@@ -43,6 +105,16 @@ func SetupRoutes(mux *http.ServeMux, db *sql.DB) {
 	})
 
 	mux.HandleFunc("/api/v1/products/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/stock-movements") {
+			stockHandler.GetStockMovements(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/stock-adjustments") {
+			stockHandler.CreateStockAdjustment(w, r)
+			return
+		}
+
 		switch r.Method {
 		case http.MethodGet:
 			productHandler.GetProductByID(w, r)
@@ -68,6 +140,15 @@ func SetupRoutes(mux *http.ServeMux, db *sql.DB) {
 	})
 
 	mux.HandleFunc("/api/v1/categories/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/products") {
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			categoryHandler.GetCategoryProducts(w, r)
+			return
+		}
+
 		switch r.Method {
 		case http.MethodGet:
 			categoryHandler.GetCategoryByID(w, r)
@@ -80,13 +161,237 @@ func SetupRoutes(mux *http.ServeMux, db *sql.DB) {
 		}
 	})
 
+	// ===== PROMOTION ROUTES =====
+	mux.HandleFunc("/api/v1/promotions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			promotionHandler.GetPromotions(w, r)
+		case http.MethodPost:
+			promotionHandler.CreatePromotion(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/promotions/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			promotionHandler.GetPromotionByID(w, r)
+		case http.MethodPut:
+			promotionHandler.UpdatePromotionByID(w, r)
+		case http.MethodDelete:
+			promotionHandler.DeletePromotionByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// ===== VOUCHER ROUTES =====
+	mux.HandleFunc("/api/v1/vouchers", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			voucherHandler.GetVouchers(w, r)
+		case http.MethodPost:
+			voucherHandler.CreateVoucher(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/vouchers/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			voucherHandler.GetVoucherByID(w, r)
+		case http.MethodPut:
+			voucherHandler.UpdateVoucherByID(w, r)
+		case http.MethodDelete:
+			voucherHandler.DeleteVoucherByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
 	// ===== TRANSACTION ROUTES =====
 	mux.HandleFunc("/api/v1/checkout", func(w http.ResponseWriter, r *http.Request) {
 		transactionHandler.HandleCheckout(w, r)
 	})
 
+	mux.HandleFunc("/api/v1/checkout/quote", func(w http.ResponseWriter, r *http.Request) {
+		transactionHandler.HandleQuote(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		transactionHandler.HandleTransactions(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/transactions/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/refund") {
+			refundHandler.CreateRefund(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/receipt") {
+			receiptHandler.GetReceipt(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/void") {
+			transactionHandler.VoidTransaction(w, r)
+			return
+		}
+
+		transactionHandler.HandleTransactionByID(w, r)
+	})
+
+	// ===== HELD CART ROUTES =====
+	mux.HandleFunc("/api/v1/held-carts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			heldCartHandler.GetHeldCarts(w, r)
+		case http.MethodPost:
+			heldCartHandler.CreateHeldCart(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/held-carts/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/resume") {
+			heldCartHandler.ResumeHeldCart(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			heldCartHandler.GetHeldCartByID(w, r)
+		case http.MethodPut:
+			heldCartHandler.UpdateHeldCartByID(w, r)
+		case http.MethodDelete:
+			heldCartHandler.ExpireHeldCartByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// ===== STOCK COUNT ROUTES =====
+	mux.HandleFunc("/api/v1/stock-counts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			stockCountHandler.GetStockCounts(w, r)
+		case http.MethodPost:
+			stockCountHandler.OpenStockCount(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/stock-counts/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/counts") {
+			stockCountHandler.SubmitStockCounts(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/post") {
+			stockCountHandler.PostStockCount(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			stockCountHandler.GetStockCountByID(w, r)
+		case http.MethodDelete:
+			stockCountHandler.CancelStockCountByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// ===== SUPPLIER ROUTES =====
+	mux.HandleFunc("/api/v1/suppliers", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			supplierHandler.GetSuppliers(w, r)
+		case http.MethodPost:
+			supplierHandler.CreateSupplier(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/suppliers/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			supplierHandler.GetSupplierByID(w, r)
+		case http.MethodPut:
+			supplierHandler.UpdateSupplierByID(w, r)
+		case http.MethodDelete:
+			supplierHandler.DeleteSupplierByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// ===== PURCHASE ORDER ROUTES =====
+	mux.HandleFunc("/api/v1/purchase-orders", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			purchaseOrderHandler.GetPurchaseOrders(w, r)
+		case http.MethodPost:
+			purchaseOrderHandler.CreatePurchaseOrder(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/purchase-orders/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/send") {
+			purchaseOrderHandler.SendPurchaseOrder(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/cancel") {
+			purchaseOrderHandler.CancelPurchaseOrder(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/receipts") {
+			purchaseOrderHandler.ReceivePurchaseOrder(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			purchaseOrderHandler.GetPurchaseOrderByID(w, r)
+		case http.MethodPut:
+			purchaseOrderHandler.UpdatePurchaseOrderByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// ===== INVENTORY ROUTES =====
+	mux.HandleFunc("/api/v1/inventory/low-stock", func(w http.ResponseWriter, r *http.Request) {
+		inventoryHandler.GetLowStock(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/inventory/reorder-suggestions", func(w http.ResponseWriter, r *http.Request) {
+		inventoryHandler.GetReorderSuggestions(w, r)
+	})
+
 	// ===== REPORT ROUTES =====
 	mux.HandleFunc("/api/v1/report/today", func(w http.ResponseWriter, r *http.Request) {
 		reportHandler(w, r)
 	})
+
+	mux.HandleFunc("/api/v1/report/tax", func(w http.ResponseWriter, r *http.Request) {
+		taxReportHandler(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/report/profit", func(w http.ResponseWriter, r *http.Request) {
+		profitReportHandler(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/report/vouchers", func(w http.ResponseWriter, r *http.Request) {
+		voucherHandler.GetVoucherRedemptionReport(w, r)
+	})
 }
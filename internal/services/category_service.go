@@ -7,12 +7,14 @@ import (
 )
 
 type CategoryService struct {
-	repo *repository.CategoryRepository
+	repo        *repository.CategoryRepository
+	productRepo *repository.ProductRepository
 }
 
-func NewCategoryService(repo *repository.CategoryRepository) *CategoryService {
+func NewCategoryService(repo *repository.CategoryRepository, productRepo *repository.ProductRepository) *CategoryService {
 	return &CategoryService{
-		repo: repo,
+		repo:        repo,
+		productRepo: productRepo,
 	}
 }
 
@@ -33,6 +35,15 @@ func (s *CategoryService) GetByID(id int) (models.Category, error) {
 	return category, nil
 }
 
+// Get products of a category
+func (s *CategoryService) GetProducts(id int) ([]models.Product, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.GetAll("", id)
+}
+
 // Create new category
 func (s *CategoryService) Create(category models.Category) (models.Category, error) {
 	// validasi sederhana
@@ -52,7 +63,7 @@ func (s *CategoryService) Update(id int, category models.Category) (models.Categ
 	return s.repo.Update(id, category)
 }
 
-// Delete category
-func (s *CategoryService) Delete(id int) error {
-	return s.repo.Delete(id)
+// Delete category, reassignTo > 0 memindahkan product ke category lain
+func (s *CategoryService) Delete(id int, reassignTo int) error {
+	return s.repo.Delete(id, reassignTo)
 }
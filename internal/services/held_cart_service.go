@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeldCart = errors.New("held cart needs cashier and at least one item with quantity > 0 and valid discounts")
+
+type HeldCartService struct {
+	repo               *repository.HeldCartRepository
+	transactionService *TransactionService
+	ttl                time.Duration
+}
+
+// NewHeldCartService, ttl adalah lama keranjang di-hold sebelum otomatis expired
+func NewHeldCartService(repo *repository.HeldCartRepository, transactionService *TransactionService, ttl time.Duration) *HeldCartService {
+	return &HeldCartService{
+		repo:               repo,
+		transactionService: transactionService,
+		ttl:                ttl,
+	}
+}
+
+// Get all held carts, filter cashier / status opsional
+func (s *HeldCartService) GetAll(cashier, status string) ([]models.HeldCart, error) {
+	return s.repo.GetAll(strings.TrimSpace(cashier), status)
+}
+
+// Get held cart by ID
+func (s *HeldCartService) GetByID(id int) (models.HeldCart, error) {
+	return s.repo.GetByID(id)
+}
+
+// Hold keranjang baru
+func (s *HeldCartService) Create(req models.HeldCartRequest) (models.HeldCart, error) {
+	cart, err := buildHeldCart(req)
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	return s.repo.Create(cart, s.ttl)
+}
+
+// Update isi held cart, masa hold dihitung ulang dari sekarang
+func (s *HeldCartService) Update(id int, req models.HeldCartRequest) (models.HeldCart, error) {
+	cart, err := buildHeldCart(req)
+	if err != nil {
+		return models.HeldCart{}, err
+	}
+
+	return s.repo.Update(id, cart, s.ttl)
+}
+
+// Expire melepas held cart tanpa checkout
+func (s *HeldCartService) Expire(id int) (models.HeldCart, error) {
+	return s.repo.Expire(id)
+}
+
+// Resume mengubah held cart menjadi transaksi lewat TransactionService.ResumeHeldCart.
+// Isi keranjang dibaca setelah held cart dikunci dan held cart ditandai resumed
+// di DB transaction yang sama dengan transaksinya.
+func (s *HeldCartService) Resume(id int, req models.ResumeHeldCartRequest) (*models.Transaction, error) {
+	return s.transactionService.ResumeHeldCart(id, req.Payments)
+}
+
+func buildHeldCart(req models.HeldCartRequest) (models.HeldCart, error) {
+	cart := models.HeldCart{
+		Cashier:      strings.TrimSpace(req.Cashier),
+		Label:        strings.TrimSpace(req.Label),
+		Discount:     req.Discount,
+		VoucherCode:  normalizeVoucherCode(req.VoucherCode),
+		CustomerRef:  strings.TrimSpace(req.CustomerRef),
+		ReserveStock: req.ReserveStock,
+	}
+
+	if cart.Cashier == "" || len(req.Items) == 0 || !req.Discount.IsValid() {
+		return models.HeldCart{}, ErrInvalidHeldCart
+	}
+
+	for _, item := range req.Items {
+		if item.Quantity <= 0 || !item.Discount.IsValid() {
+			return models.HeldCart{}, ErrInvalidHeldCart
+		}
+
+		cart.Items = append(cart.Items, models.HeldCartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Discount:  item.Discount,
+		})
+	}
+
+	return cart, nil
+}
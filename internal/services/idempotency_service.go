@@ -0,0 +1,130 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"log"
+	"time"
+)
+
+var (
+	ErrInvalidIdempotencyKey  = errors.New("Idempotency-Key must be 1-255 characters")
+	ErrIdempotencyKeyReused   = errors.New("Idempotency-Key already used with a different request")
+	ErrIdempotencyKeyInFlight = errors.New("a request with this Idempotency-Key is still being processed")
+)
+
+const (
+	// key disimpan selama 24 jam, setelah itu boleh dipakai untuk request lain
+	idempotencyKeyTTL = 24 * time.Hour
+
+	// key in-flight yang heartbeat-nya berhenti lebih lama dari ini dianggap
+	// ditinggalkan (mis. proses mati sebelum Finish) dan boleh diklaim ulang.
+	// Selama request masih berjalan, KeepAlive memperbarui heartbeat tiap
+	// idempotencyKeyHeartbeat sehingga checkout yang lambat tidak diambil alih.
+	idempotencyKeyLease     = 5 * time.Minute
+	idempotencyKeyHeartbeat = time.Minute
+
+	// jumlah key kedaluwarsa yang dihapus per batch oleh PurgeExpired
+	idempotencyPurgeBatch = 1000
+)
+
+type IdempotencyService struct {
+	repo *repository.IdempotencyRepository
+}
+
+func NewIdempotencyService(repo *repository.IdempotencyRepository) *IdempotencyService {
+	return &IdempotencyService{repo: repo}
+}
+
+// Begin mengklaim key untuk request ini. Mengembalikan record jika request
+// dengan key dan body yang sama sudah selesai (response tinggal di-replay),
+// atau nil jika request harus diproses.
+func (s *IdempotencyService) Begin(key, method, path string, body []byte) (*models.IdempotencyRecord, error) {
+	if key == "" || len(key) > 255 {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	hash := requestHash(method, path, body)
+
+	now := time.Now()
+	reserved, existing, err := s.repo.Reserve(key, hash, now.Add(-idempotencyKeyTTL), now.Add(-idempotencyKeyLease))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if existing.RequestHash != hash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.StatusCode == nil {
+		return nil, ErrIdempotencyKeyInFlight
+	}
+
+	return existing, nil
+}
+
+// Finish menyimpan response request. Hanya response sukses (2xx) yang disimpan:
+// request yang gagal tidak mengubah data, dan penolakan seperti stok kurang (409)
+// atau limit voucher bisa berubah saat retry, jadi key dilepas supaya client bisa
+// retry dengan key yang sama.
+func (s *IdempotencyService) Finish(key string, statusCode int, contentType string, response []byte) error {
+	if statusCode < 200 || statusCode >= 300 {
+		return s.repo.Release(key)
+	}
+	return s.repo.Complete(key, statusCode, contentType, response)
+}
+
+// KeepAlive memperbarui heartbeat key selama request diproses,
+// fungsi yang dikembalikan menghentikannya
+func (s *IdempotencyService) KeepAlive(key string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(idempotencyKeyHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.repo.Touch(key); err != nil {
+					log.Println("failed to extend idempotency key lease:", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// Release melepas key tanpa menyimpan response, dipakai jika request berhenti
+// sebelum Finish (handler panic)
+func (s *IdempotencyService) Release(key string) error {
+	return s.repo.Release(key)
+}
+
+// PurgeExpired menghapus semua key yang sudah kedaluwarsa per batch supaya tabel
+// tidak terus membesar, dijalankan berkala (lihat main.go)
+func (s *IdempotencyService) PurgeExpired() error {
+	expiredBefore := time.Now().Add(-idempotencyKeyTTL)
+	for {
+		deleted, err := s.repo.DeleteExpired(expiredBefore, idempotencyPurgeBatch)
+		if err != nil {
+			return err
+		}
+		if deleted < idempotencyPurgeBatch {
+			return nil
+		}
+	}
+}
+
+// requestHash mengikat key ke endpoint dan body request
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
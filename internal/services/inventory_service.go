@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	defaultReorderDays      = 30
+	maxReorderDays          = 365
+	defaultReorderCoverDays = 14
+)
+
+var ErrInvalidReorderFilter = errors.New("days must be 1-365, cover_days must be > 0 and supplier_id must be >= 0")
+
+type InventoryService struct {
+	repo *repository.InventoryRepository
+}
+
+func NewInventoryService(repo *repository.InventoryRepository) *InventoryService {
+	return &InventoryService{
+		repo: repo,
+	}
+}
+
+// Daftar product yang stoknya sudah mencapai min_stock
+func (s *InventoryService) GetLowStock() ([]models.LowStockItem, error) {
+	return s.repo.GetLowStock()
+}
+
+// GetReorderSuggestions menghitung saran order per supplier dari rata-rata penjualan
+// harian selama filter.Days hari terakhir. Target stok adalah kebutuhan filter.CoverDays
+// hari (minimal min_stock), dikurangi stok tersedia dan qty yang masih dipesan di PO,
+// lalu dibulatkan ke atas ke kelipatan reorder_qty.
+func (s *InventoryService) GetReorderSuggestions(filter models.ReorderFilter) (*models.ReorderReport, error) {
+	if filter.Days == 0 {
+		filter.Days = defaultReorderDays
+	}
+	if filter.CoverDays == 0 {
+		filter.CoverDays = defaultReorderCoverDays
+	}
+	if filter.Days < 1 || filter.Days > maxReorderDays || filter.CoverDays < 1 || filter.SupplierID < 0 {
+		return nil, ErrInvalidReorderFilter
+	}
+
+	now := time.Now()
+	candidates, err := s.repo.GetReorderCandidates(now.AddDate(0, 0, -filter.Days))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReorderReport{
+		Days:        filter.Days,
+		CoverDays:   filter.CoverDays,
+		GeneratedAt: now,
+		Suppliers:   make([]models.ReorderSupplierGroup, 0),
+	}
+
+	groups := make(map[int]int)
+	for _, c := range candidates {
+		supplierID := 0
+		if c.SupplierID != nil {
+			supplierID = *c.SupplierID
+		}
+		if filter.SupplierID > 0 && supplierID != filter.SupplierID {
+			continue
+		}
+
+		suggestion, ok := suggestReorder(c, filter)
+		if !ok {
+			continue
+		}
+
+		i, exists := groups[supplierID]
+		if !exists {
+			i = len(report.Suppliers)
+			groups[supplierID] = i
+			report.Suppliers = append(report.Suppliers, models.ReorderSupplierGroup{
+				SupplierID:   c.SupplierID,
+				SupplierName: c.SupplierName,
+			})
+		}
+
+		report.Suppliers[i].Items = append(report.Suppliers[i].Items, suggestion)
+		report.Suppliers[i].EstimatedCost += suggestion.EstimatedCost
+	}
+
+	// supplier terurut nama, product tanpa supplier paling akhir
+	sort.SliceStable(report.Suppliers, func(a, b int) bool {
+		sa, sb := report.Suppliers[a], report.Suppliers[b]
+		if (sa.SupplierID == nil) != (sb.SupplierID == nil) {
+			return sb.SupplierID == nil
+		}
+		return sa.SupplierName < sb.SupplierName
+	})
+
+	return report, nil
+}
+
+func suggestReorder(c models.ReorderCandidate, filter models.ReorderFilter) (models.ReorderSuggestion, bool) {
+	qtySold := c.QtySold
+	if qtySold < 0 {
+		qtySold = 0
+	}
+	velocity := float64(qtySold) / float64(filter.Days)
+
+	target := int(math.Ceil(velocity * float64(filter.CoverDays)))
+	if target < c.MinStock {
+		target = c.MinStock
+	}
+
+	need := target - c.Available - c.OnOrder
+	if need <= 0 {
+		return models.ReorderSuggestion{}, false
+	}
+
+	if c.ReorderQty > 0 {
+		need = (need + c.ReorderQty - 1) / c.ReorderQty * c.ReorderQty
+	}
+
+	suggestion := models.ReorderSuggestion{
+		ProductID:     c.ProductID,
+		ProductName:   c.ProductName,
+		Available:     c.Available,
+		OnOrder:       c.OnOrder,
+		MinStock:      c.MinStock,
+		ReorderQty:    c.ReorderQty,
+		QtySold:       qtySold,
+		DailyVelocity: math.Round(velocity*100) / 100,
+		SuggestedQty:  need,
+		UnitCost:      c.UnitCost,
+		EstimatedCost: need * c.UnitCost,
+	}
+
+	if velocity > 0 {
+		cover := math.Round(float64(c.Available)/velocity*10) / 10
+		suggestion.DaysOfCover = &cover
+	}
+
+	return suggestion, true
+}
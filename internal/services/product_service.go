@@ -2,23 +2,36 @@ package services
 
 import (
 	"database/sql"
+	"errors"
 	"kasir-api/internal/models"
 	"kasir-api/internal/repository"
 )
 
+var (
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrInvalidTaxClass   = errors.New("tax_class must be taxable or exempt")
+	ErrSupplierNotFound  = errors.New("supplier not found")
+	ErrInvalidStockLevel = errors.New("min_stock and reorder_qty must be >= 0")
+	ErrInvalidCostPrice  = errors.New("cost_price must be >= 0")
+)
+
 type ProductService struct {
-	repo *repository.ProductRepository
+	repo         *repository.ProductRepository
+	categoryRepo *repository.CategoryRepository
+	supplierRepo *repository.SupplierRepository
 }
 
-func NewProductService(repo *repository.ProductRepository) *ProductService {
+func NewProductService(repo *repository.ProductRepository, categoryRepo *repository.CategoryRepository, supplierRepo *repository.SupplierRepository) *ProductService {
 	return &ProductService{
-		repo: repo,
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		supplierRepo: supplierRepo,
 	}
 }
 
-// Get all products
-func (s *ProductService) GetAll(name string) ([]models.Product, error) {
-	return s.repo.GetAll(name)
+// Get all products, categoryID > 0 untuk filter per category
+func (s *ProductService) GetAll(name string, categoryID int) ([]models.Product, error) {
+	return s.repo.GetAll(name, categoryID)
 }
 
 // Get product by ID
@@ -40,6 +53,22 @@ func (s *ProductService) Create(product models.Product) (models.Product, error)
 		return models.Product{}, sql.ErrNoRows
 	}
 
+	if err := s.validateCategory(product.CategoryID); err != nil {
+		return models.Product{}, err
+	}
+
+	if err := normalizeTaxClass(&product); err != nil {
+		return models.Product{}, err
+	}
+
+	if err := s.validateStockLevel(product); err != nil {
+		return models.Product{}, err
+	}
+
+	if product.CostPrice < 0 {
+		return models.Product{}, ErrInvalidCostPrice
+	}
+
 	return s.repo.Create(product)
 }
 
@@ -50,6 +79,22 @@ func (s *ProductService) Update(id int, product models.Product) (models.Product,
 		return models.Product{}, sql.ErrNoRows
 	}
 
+	if err := s.validateCategory(product.CategoryID); err != nil {
+		return models.Product{}, err
+	}
+
+	if err := normalizeTaxClass(&product); err != nil {
+		return models.Product{}, err
+	}
+
+	if err := s.validateStockLevel(product); err != nil {
+		return models.Product{}, err
+	}
+
+	if product.CostPrice < 0 {
+		return models.Product{}, ErrInvalidCostPrice
+	}
+
 	return s.repo.Update(id, product)
 }
 
@@ -57,3 +102,51 @@ func (s *ProductService) Update(id int, product models.Product) (models.Product,
 func (s *ProductService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
+
+// validateStockLevel memastikan batas stok tidak negatif dan supplier_id (jika diisi) ada
+func (s *ProductService) validateStockLevel(product models.Product) error {
+	if product.MinStock < 0 || product.ReorderQty < 0 {
+		return ErrInvalidStockLevel
+	}
+
+	if product.SupplierID == nil {
+		return nil
+	}
+
+	if _, err := s.supplierRepo.GetByID(*product.SupplierID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSupplierNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// validateCategory memastikan category_id (jika diisi) ada di tabel categories
+func (s *ProductService) validateCategory(categoryID *int) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	if _, err := s.categoryRepo.GetByID(*categoryID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// normalizeTaxClass default ke taxable jika kosong
+func normalizeTaxClass(product *models.Product) error {
+	switch product.TaxClass {
+	case "":
+		product.TaxClass = models.TaxClassTaxable
+	case models.TaxClassTaxable, models.TaxClassExempt:
+	default:
+		return ErrInvalidTaxClass
+	}
+	return nil
+}
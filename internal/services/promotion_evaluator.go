@@ -0,0 +1,147 @@
+package services
+
+import (
+	"kasir-api/internal/models"
+	"sort"
+)
+
+// promotionCandidate adalah satu kemungkinan penerapan promo,
+// discounts berisi potongan per index baris detail
+type promotionCandidate struct {
+	promotionID int
+	discounts   map[int]int
+	total       int
+}
+
+// applyPromotions memilih promo terbaik untuk keranjang. Satu baris hanya
+// boleh kena satu promo, kandidat dengan potongan terbesar didahulukan.
+// GrossAmount tiap baris harus sudah terisi, Subtotal dan DiscountAmount
+// dikurangi/ditambah sesuai potongan promo.
+func applyPromotions(details []models.TransactionDetail, promotions []models.Promotion) {
+	lineByProduct := make(map[int]int)
+	for i := len(details) - 1; i >= 0; i-- {
+		lineByProduct[details[i].ProductID] = i
+	}
+
+	candidates := make([]promotionCandidate, 0)
+
+	for _, promo := range promotions {
+		switch promo.Type {
+		case models.PromotionTypePrice, models.PromotionTypeBuyXGetY:
+			if promo.ProductID == nil {
+				continue
+			}
+			for i, d := range details {
+				if d.ProductID != *promo.ProductID || d.Quantity <= 0 {
+					continue
+				}
+
+				discount := singleLineDiscount(promo, d)
+				if discount > 0 {
+					candidates = append(candidates, promotionCandidate{
+						promotionID: promo.ID,
+						discounts:   map[int]int{i: discount},
+						total:       discount,
+					})
+				}
+			}
+		case models.PromotionTypeBundle:
+			if c, ok := bundleCandidate(promo, details, lineByProduct); ok {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].total > candidates[b].total
+	})
+
+	claimed := make(map[int]bool)
+	for _, c := range candidates {
+		free := true
+		for i := range c.discounts {
+			if claimed[i] {
+				free = false
+				break
+			}
+		}
+		if !free {
+			continue
+		}
+
+		promotionID := c.promotionID
+		for i, discount := range c.discounts {
+			claimed[i] = true
+			details[i].PromotionID = &promotionID
+			details[i].DiscountAmount += discount
+			details[i].Subtotal -= discount
+		}
+	}
+}
+
+func singleLineDiscount(promo models.Promotion, d models.TransactionDetail) int {
+	unitPrice := d.GrossAmount / d.Quantity
+
+	switch promo.Type {
+	case models.PromotionTypePrice:
+		if promo.PromoPrice >= unitPrice {
+			return 0
+		}
+		return (unitPrice - promo.PromoPrice) * d.Quantity
+	case models.PromotionTypeBuyXGetY:
+		sets := d.Quantity / (promo.BuyQty + promo.GetQty)
+		return sets * promo.GetQty * unitPrice
+	}
+	return 0
+}
+
+func bundleCandidate(promo models.Promotion, details []models.TransactionDetail, lineByProduct map[int]int) (promotionCandidate, bool) {
+	if len(promo.Items) == 0 {
+		return promotionCandidate{}, false
+	}
+
+	// jumlah set bundle yang bisa dibentuk dari keranjang
+	sets := -1
+	for _, item := range promo.Items {
+		i, ok := lineByProduct[item.ProductID]
+		if !ok {
+			return promotionCandidate{}, false
+		}
+		n := details[i].Quantity / item.Quantity
+		if sets < 0 || n < sets {
+			sets = n
+		}
+	}
+	if sets <= 0 {
+		return promotionCandidate{}, false
+	}
+
+	normalPrice := 0
+	portions := make([]int, len(promo.Items))
+	for k, item := range promo.Items {
+		d := details[lineByProduct[item.ProductID]]
+		portions[k] = d.GrossAmount / d.Quantity * item.Quantity * sets
+		normalPrice += portions[k]
+	}
+
+	total := normalPrice - promo.PromoPrice*sets
+	if total <= 0 {
+		return promotionCandidate{}, false
+	}
+
+	// potongan dibagi ke tiap baris sesuai porsi harga normalnya
+	discounts := make(map[int]int)
+	cumulative, allocated := 0, 0
+	for k, item := range promo.Items {
+		cumulative += portions[k]
+		share := total*cumulative/normalPrice - allocated
+		allocated += share
+		discounts[lineByProduct[item.ProductID]] += share
+	}
+
+	return promotionCandidate{
+		promotionID: promo.ID,
+		discounts:   discounts,
+		total:       total,
+	}, true
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"testing"
+
+	"kasir-api/internal/models"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// line membuat baris detail dengan harga satuan unitPrice
+func line(productID, quantity, unitPrice int) models.TransactionDetail {
+	return models.TransactionDetail{
+		ProductID:   productID,
+		Quantity:    quantity,
+		GrossAmount: quantity * unitPrice,
+		Subtotal:    quantity * unitPrice,
+	}
+}
+
+func TestApplyPromotions(t *testing.T) {
+	type want struct {
+		promotionID int // 0 = tanpa promo
+		discount    int
+	}
+
+	tests := []struct {
+		name       string
+		details    []models.TransactionDetail
+		promotions []models.Promotion
+		want       []want
+	}{
+		{
+			name:    "no matching promotion",
+			details: []models.TransactionDetail{line(1, 2, 10000)},
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionTypePrice, ProductID: intPtr(2), PromoPrice: 5000},
+			},
+			want: []want{{0, 0}},
+		},
+		{
+			name:    "promo price not below normal price is ignored",
+			details: []models.TransactionDetail{line(1, 2, 10000)},
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionTypePrice, ProductID: intPtr(1), PromoPrice: 12000},
+			},
+			want: []want{{0, 0}},
+		},
+		{
+			name:    "buy x get y beats promo price on the same line",
+			details: []models.TransactionDetail{line(1, 3, 10000)},
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionTypePrice, ProductID: intPtr(1), PromoPrice: 8000},
+				{ID: 2, Type: models.PromotionTypeBuyXGetY, ProductID: intPtr(1), BuyQty: 2, GetQty: 1},
+			},
+			want: []want{{2, 10000}},
+		},
+		{
+			name:    "buy x get y only counts complete sets",
+			details: []models.TransactionDetail{line(1, 5, 10000)},
+			promotions: []models.Promotion{
+				{ID: 2, Type: models.PromotionTypeBuyXGetY, ProductID: intPtr(1), BuyQty: 2, GetQty: 1},
+			},
+			want: []want{{2, 10000}},
+		},
+		{
+			name:    "bundle beats single line promo and claims every line",
+			details: []models.TransactionDetail{line(1, 1, 10000), line(2, 1, 5000)},
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionTypePrice, ProductID: intPtr(2), PromoPrice: 3000},
+				{ID: 3, Type: models.PromotionTypeBundle, PromoPrice: 12000, Items: []models.PromotionItem{
+					{ProductID: 1, Quantity: 1},
+					{ProductID: 2, Quantity: 1},
+				}},
+			},
+			want: []want{{3, 2000}, {3, 1000}},
+		},
+		{
+			name:    "single line promo beats bundle, bundle cannot claim a taken line",
+			details: []models.TransactionDetail{line(1, 1, 10000), line(2, 1, 5000)},
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionTypePrice, ProductID: intPtr(2), PromoPrice: 3000},
+				{ID: 3, Type: models.PromotionTypeBundle, PromoPrice: 14000, Items: []models.PromotionItem{
+					{ProductID: 1, Quantity: 1},
+					{ProductID: 2, Quantity: 1},
+				}},
+			},
+			want: []want{{0, 0}, {1, 2000}},
+		},
+		{
+			name:    "bundle discount remainder goes to the last item",
+			details: []models.TransactionDetail{line(1, 1, 1000), line(2, 1, 1000), line(3, 1, 1000)},
+			promotions: []models.Promotion{
+				{ID: 4, Type: models.PromotionTypeBundle, PromoPrice: 2000, Items: []models.PromotionItem{
+					{ProductID: 1, Quantity: 1},
+					{ProductID: 2, Quantity: 1},
+					{ProductID: 3, Quantity: 1},
+				}},
+			},
+			want: []want{{4, 333}, {4, 333}, {4, 334}},
+		},
+		{
+			name:    "bundle needs every item",
+			details: []models.TransactionDetail{line(1, 2, 10000)},
+			promotions: []models.Promotion{
+				{ID: 3, Type: models.PromotionTypeBundle, PromoPrice: 1000, Items: []models.PromotionItem{
+					{ProductID: 1, Quantity: 1},
+					{ProductID: 2, Quantity: 1},
+				}},
+			},
+			want: []want{{0, 0}},
+		},
+		{
+			name:    "equal discounts keep the first promotion",
+			details: []models.TransactionDetail{line(1, 1, 10000)},
+			promotions: []models.Promotion{
+				{ID: 5, Type: models.PromotionTypePrice, ProductID: intPtr(1), PromoPrice: 7000},
+				{ID: 6, Type: models.PromotionTypePrice, ProductID: intPtr(1), PromoPrice: 7000},
+			},
+			want: []want{{5, 3000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyPromotions(tt.details, tt.promotions)
+
+			for i, d := range tt.details {
+				promotionID := 0
+				if d.PromotionID != nil {
+					promotionID = *d.PromotionID
+				}
+
+				w := tt.want[i]
+				if promotionID != w.promotionID || d.DiscountAmount != w.discount {
+					t.Errorf("line %d: promotion %d discount %d, want promotion %d discount %d",
+						i, promotionID, d.DiscountAmount, w.promotionID, w.discount)
+				}
+				if d.Subtotal != d.GrossAmount-d.DiscountAmount {
+					t.Errorf("line %d: subtotal %d, want %d", i, d.Subtotal, d.GrossAmount-d.DiscountAmount)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+type PromotionService struct {
+	repo        *repository.PromotionRepository
+	productRepo *repository.ProductRepository
+}
+
+func NewPromotionService(repo *repository.PromotionRepository, productRepo *repository.ProductRepository) *PromotionService {
+	return &PromotionService{
+		repo:        repo,
+		productRepo: productRepo,
+	}
+}
+
+// Get all promotions
+func (s *PromotionService) GetAll() ([]models.Promotion, error) {
+	return s.repo.GetAll()
+}
+
+// Get promotion by ID
+func (s *PromotionService) GetByID(id int) (models.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create new promotion
+func (s *PromotionService) Create(promo models.Promotion) (models.Promotion, error) {
+	if err := s.validate(&promo); err != nil {
+		return models.Promotion{}, err
+	}
+
+	return s.repo.Create(promo)
+}
+
+// Update promotion
+func (s *PromotionService) Update(id int, promo models.Promotion) (models.Promotion, error) {
+	if err := s.validate(&promo); err != nil {
+		return models.Promotion{}, err
+	}
+
+	return s.repo.Update(id, promo)
+}
+
+// Delete promotion
+func (s *PromotionService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func (s *PromotionService) validate(promo *models.Promotion) error {
+	promo.Name = strings.TrimSpace(promo.Name)
+	if promo.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+
+	switch promo.Type {
+	case models.PromotionTypePrice:
+		if promo.ProductID == nil || promo.PromoPrice < 0 {
+			return fmt.Errorf("%w: price promotion needs product_id and promo_price >= 0", ErrInvalidPromotion)
+		}
+		promo.BuyQty, promo.GetQty, promo.Items = 0, 0, nil
+	case models.PromotionTypeBuyXGetY:
+		if promo.ProductID == nil || promo.BuyQty <= 0 || promo.GetQty <= 0 {
+			return fmt.Errorf("%w: buy_x_get_y promotion needs product_id, buy_qty > 0 and get_qty > 0", ErrInvalidPromotion)
+		}
+		promo.PromoPrice, promo.Items = 0, nil
+	case models.PromotionTypeBundle:
+		if len(promo.Items) == 0 || promo.PromoPrice <= 0 {
+			return fmt.Errorf("%w: bundle promotion needs items and promo_price > 0", ErrInvalidPromotion)
+		}
+		seen := make(map[int]bool)
+		for _, item := range promo.Items {
+			if item.Quantity <= 0 || seen[item.ProductID] {
+				return fmt.Errorf("%w: bundle items need unique product_id and quantity > 0", ErrInvalidPromotion)
+			}
+			seen[item.ProductID] = true
+		}
+		promo.ProductID, promo.BuyQty, promo.GetQty = nil, 0, 0
+	default:
+		return fmt.Errorf("%w: type must be price, buy_x_get_y or bundle", ErrInvalidPromotion)
+	}
+
+	if promo.StartAt != nil && promo.EndAt != nil && !promo.EndAt.After(*promo.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidPromotion)
+	}
+
+	for _, d := range promo.DaysOfWeek {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("%w: days_of_week must be 0 (Sunday) to 6 (Saturday)", ErrInvalidPromotion)
+		}
+	}
+
+	for _, clock := range []string{promo.StartTime, promo.EndTime} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return fmt.Errorf("%w: start_time and end_time must use HH:MM", ErrInvalidPromotion)
+		}
+	}
+	if promo.StartTime != "" && promo.EndTime != "" && promo.EndTime <= promo.StartTime {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidPromotion)
+	}
+
+	productIDs := make([]int, 0, len(promo.Items)+1)
+	if promo.ProductID != nil {
+		productIDs = append(productIDs, *promo.ProductID)
+	}
+	for _, item := range promo.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	for _, id := range productIDs {
+		if _, err := s.productRepo.GetByID(id); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: product id %d not found", ErrInvalidPromotion, id)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+var (
+	ErrInvalidPurchaseOrder       = errors.New("supplier_id and at least one line with unique product_id, quantity > 0 and unit_cost >= 0 are required")
+	ErrInvalidPurchaseOrderFilter = errors.New("invalid purchase order filter")
+	ErrInvalidGoodsReceipt        = errors.New("received_by and at least one line with unique product_id, quantity > 0 and unit_cost >= 0 are required")
+)
+
+type PurchaseOrderService struct {
+	repo *repository.PurchaseOrderRepository
+}
+
+func NewPurchaseOrderService(repo *repository.PurchaseOrderRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		repo: repo,
+	}
+}
+
+// Get all purchase orders, filter supplier / status opsional
+func (s *PurchaseOrderService) GetAll(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	if filter.SupplierID < 0 {
+		return nil, ErrInvalidPurchaseOrderFilter
+	}
+
+	switch filter.Status {
+	case "",
+		models.PurchaseOrderStatusDraft,
+		models.PurchaseOrderStatusSent,
+		models.PurchaseOrderStatusPartiallyReceived,
+		models.PurchaseOrderStatusReceived,
+		models.PurchaseOrderStatusCancelled:
+	default:
+		return nil, ErrInvalidPurchaseOrderFilter
+	}
+
+	return s.repo.GetAll(filter)
+}
+
+// Get purchase order by ID beserta baris dan penerimaannya
+func (s *PurchaseOrderService) GetByID(id int) (models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create purchase order baru berstatus draft
+func (s *PurchaseOrderService) Create(req models.PurchaseOrderRequest) (models.PurchaseOrder, error) {
+	po, err := buildPurchaseOrder(req)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return s.repo.Create(po)
+}
+
+// Update purchase order yang masih draft
+func (s *PurchaseOrderService) Update(id int, req models.PurchaseOrderRequest) (models.PurchaseOrder, error) {
+	po, err := buildPurchaseOrder(req)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return s.repo.Update(id, po)
+}
+
+// Send menandai PO draft sudah dikirim ke supplier
+func (s *PurchaseOrderService) Send(id int) (models.PurchaseOrder, error) {
+	return s.repo.SetStatus(id, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusDraft)
+}
+
+// Cancel membatalkan PO yang belum ada penerimaan barangnya
+func (s *PurchaseOrderService) Cancel(id int) (models.PurchaseOrder, error) {
+	return s.repo.SetStatus(id, models.PurchaseOrderStatusCancelled,
+		models.PurchaseOrderStatusDraft,
+		models.PurchaseOrderStatusSent,
+	)
+}
+
+// Receive mencatat penerimaan barang dan menambah stok
+func (s *PurchaseOrderService) Receive(id int, req models.GoodsReceiptRequest) (models.PurchaseOrder, error) {
+	req.ReceivedBy = strings.TrimSpace(req.ReceivedBy)
+	req.Note = strings.TrimSpace(req.Note)
+
+	if req.ReceivedBy == "" || len(req.Lines) == 0 {
+		return models.PurchaseOrder{}, ErrInvalidGoodsReceipt
+	}
+
+	seen := make(map[int]bool)
+	for _, l := range req.Lines {
+		if l.ProductID <= 0 || l.Quantity <= 0 || (l.UnitCost != nil && *l.UnitCost < 0) || seen[l.ProductID] {
+			return models.PurchaseOrder{}, ErrInvalidGoodsReceipt
+		}
+		seen[l.ProductID] = true
+	}
+
+	return s.repo.Receive(id, req)
+}
+
+func buildPurchaseOrder(req models.PurchaseOrderRequest) (models.PurchaseOrder, error) {
+	if req.SupplierID <= 0 || len(req.Lines) == 0 {
+		return models.PurchaseOrder{}, ErrInvalidPurchaseOrder
+	}
+
+	po := models.PurchaseOrder{
+		SupplierID: req.SupplierID,
+		Note:       strings.TrimSpace(req.Note),
+		CreatedBy:  strings.TrimSpace(req.CreatedBy),
+		Lines:      make([]models.PurchaseOrderLine, len(req.Lines)),
+	}
+
+	seen := make(map[int]bool)
+	for i, l := range req.Lines {
+		if l.ProductID <= 0 || l.Quantity <= 0 || l.UnitCost < 0 || seen[l.ProductID] {
+			return models.PurchaseOrder{}, ErrInvalidPurchaseOrder
+		}
+		seen[l.ProductID] = true
+
+		po.Lines[i] = models.PurchaseOrderLine{
+			ProductID: l.ProductID,
+			Quantity:  l.Quantity,
+			UnitCost:  l.UnitCost,
+		}
+	}
+
+	return po, nil
+}
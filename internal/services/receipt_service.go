@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+var ErrInvalidReceiptFormat = errors.New("format must be text, escpos or pdf")
+
+// defaultReceiptTemplate dipakai jika RECEIPT_TEMPLATE_FILE tidak diisi.
+// Fungsi yang tersedia: center, row, line, rupiah, paymentLabel.
+const defaultReceiptTemplate = `{{center .Store.Name}}
+{{- if .Store.Address}}
+{{center .Store.Address}}{{end}}
+{{- if .Store.Phone}}
+{{center (print "Telp " .Store.Phone)}}{{end}}
+{{- if .Store.TaxID}}
+{{center (print "NPWP " .Store.TaxID)}}{{end}}
+{{line}}
+{{row "No" (or .Transaction.InvoiceNumber (print "#" .Transaction.ID))}}
+{{row "Tanggal" (.Transaction.CreatedAt.Format "02/01/2006 15:04")}}
+{{- if .Voided}}
+{{center "*** VOID ***"}}{{end}}
+{{line}}
+{{- range .Transaction.Details}}
+{{.ProductName}}
+{{row (print "  " .Quantity " x " (rupiah .UnitPrice)) (rupiah .GrossAmount)}}
+{{- if .DiscountAmount}}
+{{row "  Diskon" (print "-" (rupiah .DiscountAmount))}}{{end}}
+{{- end}}
+{{line}}
+{{row "Subtotal" (rupiah .Transaction.GrossAmount)}}
+{{- if .Transaction.DiscountAmount}}
+{{row "Diskon" (print "-" (rupiah .Transaction.DiscountAmount))}}{{end}}
+{{- if .Transaction.ServiceChargeAmount}}
+{{row "Service" (rupiah .Transaction.ServiceChargeAmount)}}{{end}}
+{{- if .Transaction.TaxAmount}}
+{{row (print "PPN " .Transaction.TaxRate "%" (or (and .Transaction.TaxInclusive " (incl)") "")) (rupiah .Transaction.TaxAmount)}}{{end}}
+{{row "TOTAL" (rupiah .Transaction.TotalAmount)}}
+{{line}}
+{{- range .Transaction.Payments}}
+{{row (paymentLabel .Method) (rupiah .Amount)}}
+{{- end}}
+{{row "Kembali" (rupiah .Transaction.ChangeAmount)}}
+{{line}}
+{{- if .Store.Footer}}
+{{center .Store.Footer}}{{end}}
+`
+
+type receiptData struct {
+	Store       models.StoreInfo
+	Transaction *models.Transaction
+	Voided      bool
+}
+
+type ReceiptService struct {
+	repo     *repository.TransactionRepository
+	store    models.StoreInfo
+	width    int
+	template *template.Template
+}
+
+// NewReceiptService mem-parse template struk, templateText kosong berarti
+// memakai template bawaan. Template yang tidak valid membuat aplikasi gagal start.
+func NewReceiptService(repo *repository.TransactionRepository, store models.StoreInfo, width int, templateText string) *ReceiptService {
+	if templateText == "" {
+		templateText = defaultReceiptTemplate
+	}
+
+	s := &ReceiptService{
+		repo:  repo,
+		store: store,
+		width: width,
+	}
+
+	s.template = template.Must(template.New("receipt").Funcs(template.FuncMap{
+		"center":       s.center,
+		"row":          s.row,
+		"line":         func() string { return strings.Repeat("-", s.width) },
+		"rupiah":       formatRupiah,
+		"paymentLabel": paymentLabel,
+	}).Parse(templateText))
+
+	return s
+}
+
+// Render struk transaksi, mengembalikan isi dan content type
+func (s *ReceiptService) Render(transactionID int, format string) ([]byte, string, error) {
+	if format == "" {
+		format = models.ReceiptFormatText
+	}
+
+	if format != models.ReceiptFormatText &&
+		format != models.ReceiptFormatESCPOS &&
+		format != models.ReceiptFormatPDF {
+		return nil, "", ErrInvalidReceiptFormat
+	}
+
+	transaction, err := s.repo.GetByID(transactionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	err = s.template.Execute(&buf, receiptData{
+		Store:       s.store,
+		Transaction: transaction,
+		Voided:      transaction.Status == models.TransactionStatusVoided,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	text := buf.String()
+
+	switch format {
+	case models.ReceiptFormatESCPOS:
+		return renderESCPOS(text), "application/octet-stream", nil
+	case models.ReceiptFormatPDF:
+		return renderPDF(strings.Split(strings.TrimRight(text, "\n"), "\n"), s.width), "application/pdf", nil
+	default:
+		return []byte(text), "text/plain; charset=utf-8", nil
+	}
+}
+
+func (s *ReceiptService) center(text string) string {
+	n := utf8.RuneCountInString(text)
+	if n >= s.width {
+		return text
+	}
+	return strings.Repeat(" ", (s.width-n)/2) + text
+}
+
+// row menulis left rata kiri dan right rata kanan dalam satu baris
+func (s *ReceiptService) row(left, right string) string {
+	gap := s.width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		gap = 1
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+// formatRupiah memformat angka dengan pemisah ribuan titik, mis. 15.000
+func formatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%d", amount)
+	var parts []string
+	for len(digits) > 3 {
+		parts = append([]string{digits[len(digits)-3:]}, parts...)
+		digits = digits[:len(digits)-3]
+	}
+	parts = append([]string{digits}, parts...)
+
+	return sign + strings.Join(parts, ".")
+}
+
+func paymentLabel(method string) string {
+	switch method {
+	case models.PaymentMethodCash:
+		return "Tunai"
+	case models.PaymentMethodDebitCard:
+		return "Kartu Debit"
+	case models.PaymentMethodEWallet:
+		return "E-Wallet"
+	case models.PaymentMethodQRIS:
+		return "QRIS"
+	case models.PaymentMethodBankTransfer:
+		return "Transfer Bank"
+	}
+	return method
+}
+
+// renderESCPOS membungkus struk teks dengan perintah printer thermal:
+// inisialisasi (ESC @), feed beberapa baris lalu potong kertas (GS V)
+func renderESCPOS(text string) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0x1B, 0x40})
+	buf.WriteString(text)
+	buf.Write([]byte{0x1B, 0x64, 0x04})
+	buf.Write([]byte{0x1D, 0x56, 0x42, 0x00})
+	return buf.Bytes()
+}
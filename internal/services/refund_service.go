@@ -0,0 +1,34 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+)
+
+var ErrInvalidRefundRequest = errors.New("refund must contain at least one item with quantity > 0")
+
+type RefundService struct {
+	repo *repository.RefundRepository
+}
+
+func NewRefundService(repo *repository.RefundRepository) *RefundService {
+	return &RefundService{
+		repo: repo,
+	}
+}
+
+// Refund sebagian atau seluruh item dari transaksi
+func (s *RefundService) Refund(transactionID int, req models.RefundRequest) (*models.Refund, error) {
+	if len(req.Items) == 0 {
+		return nil, ErrInvalidRefundRequest
+	}
+
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidRefundRequest
+		}
+	}
+
+	return s.repo.CreateRefund(transactionID, req)
+}
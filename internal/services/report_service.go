@@ -1,8 +1,16 @@
 package services
 
 import (
+	"errors"
 	"kasir-api/internal/models"
 	"kasir-api/internal/repository"
+	"math"
+	"time"
+)
+
+var (
+	ErrInvalidDateRange   = errors.New("end_date must not be before start_date")
+	ErrInvalidProfitGroup = errors.New("group_by must be day, product or category")
 )
 
 type ReportService struct {
@@ -14,7 +22,7 @@ func NewReportService(repo *repository.ReportRepository) *ReportService {
 }
 
 func (s *ReportService) GetTodaySalesReport() (*models.SalesReport, error) {
-	totalRevenue, totalTransaksi, err := s.repo.GetTodaySummary()
+	report, err := s.repo.GetTodaySummary()
 	if err != nil {
 		return nil, err
 	}
@@ -24,12 +32,93 @@ func (s *ReportService) GetTodaySalesReport() (*models.SalesReport, error) {
 		return nil, err
 	}
 
-	return &models.SalesReport{
-		TotalRevenue:   totalRevenue,
-		TotalTransaksi: totalTransaksi,
-		ProdukTerlaris: models.BestSeller{
-			Nama:       nama,
-			QtyTerjual: qty,
-		},
-	}, nil
+	pembayaran, err := s.repo.GetTodayPaymentBreakdown()
+	if err != nil {
+		return nil, err
+	}
+
+	report.ProdukTerlaris = models.BestSeller{
+		Nama:       nama,
+		QtyTerjual: qty,
+	}
+	report.Pembayaran = pembayaran
+
+	return report, nil
+}
+
+// Ringkasan pajak untuk periode [start, end), default hari ini
+func (s *ReportService) GetTaxSummary(start, end *time.Time) (*models.TaxSummary, error) {
+	from, to, err := reportRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTaxSummary(from, to)
+}
+
+// GetProfitReport laba kotor periode [start, end) per hari, product atau category,
+// default hari ini dan per hari
+func (s *ReportService) GetProfitReport(start, end *time.Time, groupBy string) (*models.ProfitReport, error) {
+	switch groupBy {
+	case "":
+		groupBy = models.ProfitGroupByDay
+	case models.ProfitGroupByDay, models.ProfitGroupByProduct, models.ProfitGroupByCategory:
+	default:
+		return nil, ErrInvalidProfitGroup
+	}
+
+	from, to, err := reportRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetProfit(from, to, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProfitReport{
+		StartDate: from,
+		EndDate:   to,
+		GroupBy:   groupBy,
+		Rows:      rows,
+	}
+
+	for i := range rows {
+		rows[i].GrossMargin = grossMargin(rows[i].GrossProfit, rows[i].Revenue)
+		report.Revenue += rows[i].Revenue
+		report.COGS += rows[i].COGS
+	}
+	report.GrossProfit = report.Revenue - report.COGS
+	report.GrossMargin = grossMargin(report.GrossProfit, report.Revenue)
+
+	return report, nil
+}
+
+// grossMargin laba kotor dalam persen dari revenue, dibulatkan 2 desimal
+func grossMargin(profit, revenue int) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return math.Round(float64(profit)/float64(revenue)*10000) / 100
+}
+
+// reportRange mengisi periode laporan [start, end) yang kosong, default hari ini
+func reportRange(start, end *time.Time) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if start != nil {
+		from = *start
+	}
+
+	to := from.AddDate(0, 0, 1)
+	if end != nil {
+		to = *end
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+
+	return from, to, nil
 }
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+var (
+	ErrInvalidStockCount       = errors.New("created_by is required")
+	ErrInvalidStockCountStatus = errors.New("status must be open, posted or cancelled")
+	ErrInvalidStockCountItems  = errors.New("counted_by and at least one item with unique product_id and counted_qty >= 0 are required")
+	ErrInvalidStockCountPost   = errors.New("posted_by is required")
+)
+
+type StockCountService struct {
+	repo *repository.StockCountRepository
+}
+
+func NewStockCountService(repo *repository.StockCountRepository) *StockCountService {
+	return &StockCountService{
+		repo: repo,
+	}
+}
+
+// Get all sesi stock opname, filter status opsional
+func (s *StockCountService) GetAll(status string) ([]models.StockCount, error) {
+	switch status {
+	case "", models.StockCountStatusOpen, models.StockCountStatusPosted, models.StockCountStatusCancelled:
+	default:
+		return nil, ErrInvalidStockCountStatus
+	}
+
+	return s.repo.GetAll(status)
+}
+
+// Get sesi stock opname by ID beserta selisihnya
+func (s *StockCountService) GetByID(id int) (models.StockCount, error) {
+	return s.repo.GetByID(id)
+}
+
+// Open sesi stock opname baru
+func (s *StockCountService) Open(req models.OpenStockCountRequest) (models.StockCount, error) {
+	count := models.StockCount{
+		Note:      strings.TrimSpace(req.Note),
+		CreatedBy: strings.TrimSpace(req.CreatedBy),
+	}
+
+	if count.CreatedBy == "" {
+		return models.StockCount{}, ErrInvalidStockCount
+	}
+
+	return s.repo.Open(count)
+}
+
+// SubmitCounts menyimpan hasil hitung sebagian dari satu petugas
+func (s *StockCountService) SubmitCounts(id int, req models.StockCountSubmission) (models.StockCount, error) {
+	req.CountedBy = strings.TrimSpace(req.CountedBy)
+	if req.CountedBy == "" || len(req.Items) == 0 {
+		return models.StockCount{}, ErrInvalidStockCountItems
+	}
+
+	seen := make(map[int]bool)
+	for _, entry := range req.Items {
+		if entry.ProductID <= 0 || entry.CountedQty < 0 || seen[entry.ProductID] {
+			return models.StockCount{}, ErrInvalidStockCountItems
+		}
+		seen[entry.ProductID] = true
+	}
+
+	return s.repo.SubmitCounts(id, req)
+}
+
+// Post menerapkan selisih hasil hitung ke stok dan menutup sesi
+func (s *StockCountService) Post(id int, req models.PostStockCountRequest) (models.StockCount, error) {
+	postedBy := strings.TrimSpace(req.PostedBy)
+	if postedBy == "" {
+		return models.StockCount{}, ErrInvalidStockCountPost
+	}
+
+	return s.repo.Post(id, postedBy)
+}
+
+// Cancel menutup sesi tanpa mengubah stok
+func (s *StockCountService) Cancel(id int) (models.StockCount, error) {
+	return s.repo.Cancel(id)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+const (
+	defaultStockMovementLimit = 50
+	maxStockMovementLimit     = 200
+)
+
+var (
+	ErrInvalidStockMovementFilter = errors.New("invalid stock movement filter")
+	ErrInvalidStockAdjustment     = errors.New("reason must be damaged, lost or expired with negative delta, or found with positive delta")
+)
+
+type StockService struct {
+	movementRepo *repository.StockMovementRepository
+}
+
+func NewStockService(movementRepo *repository.StockMovementRepository) *StockService {
+	return &StockService{
+		movementRepo: movementRepo,
+	}
+}
+
+// Kartu stok satu product, terbaru lebih dulu
+func (s *StockService) GetMovements(filter models.StockMovementFilter) (*models.StockMovementList, error) {
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, ErrInvalidStockMovementFilter
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultStockMovementLimit
+	}
+	if filter.Limit > maxStockMovementLimit {
+		filter.Limit = maxStockMovementLimit
+	}
+
+	movements, total, err := s.movementRepo.GetByProduct(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StockMovementList{
+		Data:  movements,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
+
+// Adjust menerapkan penyesuaian stok manual
+func (s *StockService) Adjust(productID int, req models.StockAdjustmentRequest) (models.StockAdjustment, error) {
+	switch req.Reason {
+	case models.AdjustmentReasonDamaged, models.AdjustmentReasonLost, models.AdjustmentReasonExpired:
+		if req.Delta >= 0 {
+			return models.StockAdjustment{}, ErrInvalidStockAdjustment
+		}
+	case models.AdjustmentReasonFound:
+		if req.Delta <= 0 {
+			return models.StockAdjustment{}, ErrInvalidStockAdjustment
+		}
+	default:
+		return models.StockAdjustment{}, ErrInvalidStockAdjustment
+	}
+
+	return s.movementRepo.CreateAdjustment(models.StockAdjustment{
+		ProductID: productID,
+		Delta:     req.Delta,
+		Reason:    req.Reason,
+		Note:      strings.TrimSpace(req.Note),
+		CreatedBy: strings.TrimSpace(req.CreatedBy),
+	})
+}
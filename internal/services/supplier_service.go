@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+var ErrInvalidSupplier = errors.New("supplier name is required")
+
+type SupplierService struct {
+	repo *repository.SupplierRepository
+}
+
+func NewSupplierService(repo *repository.SupplierRepository) *SupplierService {
+	return &SupplierService{
+		repo: repo,
+	}
+}
+
+// Get all suppliers
+func (s *SupplierService) GetAll() ([]models.Supplier, error) {
+	return s.repo.GetAll()
+}
+
+// Get supplier by ID
+func (s *SupplierService) GetByID(id int) (models.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create new supplier
+func (s *SupplierService) Create(supplier models.Supplier) (models.Supplier, error) {
+	supplier, err := normalizeSupplier(supplier)
+	if err != nil {
+		return models.Supplier{}, err
+	}
+
+	return s.repo.Create(supplier)
+}
+
+// Update supplier
+func (s *SupplierService) Update(id int, supplier models.Supplier) (models.Supplier, error) {
+	supplier, err := normalizeSupplier(supplier)
+	if err != nil {
+		return models.Supplier{}, err
+	}
+
+	return s.repo.Update(id, supplier)
+}
+
+// Delete supplier yang belum punya purchase order
+func (s *SupplierService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func normalizeSupplier(supplier models.Supplier) (models.Supplier, error) {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	supplier.Phone = strings.TrimSpace(supplier.Phone)
+	supplier.Email = strings.TrimSpace(supplier.Email)
+	supplier.Address = strings.TrimSpace(supplier.Address)
+
+	if supplier.Name == "" {
+		return models.Supplier{}, ErrInvalidSupplier
+	}
+	return supplier, nil
+}
@@ -1,20 +1,314 @@
 package services
 
 import (
+	"errors"
 	"kasir-api/internal/models"
 	"kasir-api/internal/repository"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTransactionLimit = 20
+	maxTransactionLimit     = 100
+	maxCustomerRefLength    = 100
+)
+
+var (
+	ErrInvalidTransactionFilter = errors.New("invalid transaction filter")
+	ErrInvalidVoidRequest       = errors.New("voided_by and reason are required")
+	ErrInvalidPayment           = errors.New("at least one payment with a valid method and amount > 0 is required")
+	ErrInvalidDiscount          = errors.New("discount type must be percentage (0-100) or fixed (>= 0)")
+	ErrInvalidCustomerRef       = errors.New("customer_ref must be at most 100 characters")
 )
 
 type TransactionService struct {
-	repo *repository.TransactionRepository
+	repo          *repository.TransactionRepository
+	promotionRepo *repository.PromotionRepository
+	tax           models.TaxSettings
+	invoice       models.InvoiceSettings
 }
 
-func NewTransactionService(repo *repository.TransactionRepository) *TransactionService {
+func NewTransactionService(repo *repository.TransactionRepository, promotionRepo *repository.PromotionRepository, tax models.TaxSettings, invoice models.InvoiceSettings) *TransactionService {
 	return &TransactionService{
-		repo: repo,
+		repo:          repo,
+		promotionRepo: promotionRepo,
+		tax:           tax,
+		invoice:       invoice,
+	}
+}
+
+// Checkout memvalidasi keranjang lebih dulu (error sama dengan Quote), baru pembayaran
+func (s *TransactionService) Checkout(req models.CheckoutRequest) (*models.Transaction, error) {
+	req, err := normalizeCart(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePayments(req.Payments); err != nil {
+		return nil, err
+	}
+
+	opts, err := s.checkoutOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateTransaction(req, opts)
+}
+
+// ResumeHeldCart checkout isi held cart id. Repository membaca isi keranjang di dalam
+// DB transaction checkout setelah held cart dikunci, lalu memvalidasinya dengan
+// normalizeCart, sehingga perubahan keranjang yang bersamaan tidak ikut terjual basi.
+func (s *TransactionService) ResumeHeldCart(id int, payments []models.CheckoutPayment) (*models.Transaction, error) {
+	if err := validatePayments(payments); err != nil {
+		return nil, err
+	}
+
+	opts, err := s.checkoutOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts.NormalizeCart = normalizeCart
+
+	return s.repo.CreateTransaction(models.CheckoutRequest{
+		Payments:   payments,
+		HeldCartID: id,
+	}, opts)
+}
+
+func validatePayments(payments []models.CheckoutPayment) error {
+	if len(payments) == 0 {
+		return ErrInvalidPayment
+	}
+
+	for _, p := range payments {
+		if p.Amount <= 0 || !isValidPaymentMethod(p.Method) {
+			return ErrInvalidPayment
+		}
+	}
+	return nil
+}
+
+// Quote menghitung harga keranjang seperti Checkout tanpa menyimpan apa pun,
+// pembayaran tidak diperiksa
+func (s *TransactionService) Quote(req models.CheckoutRequest) (*models.CheckoutQuote, error) {
+	req, err := normalizeCart(req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := s.checkoutOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.QuoteTransaction(req, opts)
+}
+
+// normalizeCart merapikan kode voucher / customer, memvalidasi item dan diskon,
+// lalu menggabungkan baris dengan product_id yang sama
+func normalizeCart(req models.CheckoutRequest) (models.CheckoutRequest, error) {
+	req.VoucherCode = normalizeVoucherCode(req.VoucherCode)
+	req.CustomerRef = strings.TrimSpace(req.CustomerRef)
+
+	if len(req.CustomerRef) > maxCustomerRefLength {
+		return req, ErrInvalidCustomerRef
+	}
+
+	if len(req.Items) == 0 {
+		return req, &repository.CheckoutError{Items: []models.CheckoutItemError{
+			{Reason: models.CheckoutErrorEmptyCart},
+		}}
+	}
+
+	var invalid []models.CheckoutItemError
+	for _, item := range req.Items {
+		switch {
+		case item.ProductID <= 0:
+			invalid = append(invalid, models.CheckoutItemError{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Reason:    models.CheckoutErrorInvalidProduct,
+			})
+		case item.Quantity <= 0:
+			invalid = append(invalid, models.CheckoutItemError{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Reason:    models.CheckoutErrorInvalidQuantity,
+			})
+		}
+	}
+	if len(invalid) > 0 {
+		return req, &repository.CheckoutError{Items: invalid}
+	}
+
+	if !req.Discount.IsValid() {
+		return req, ErrInvalidDiscount
+	}
+	for _, item := range req.Items {
+		if !item.Discount.IsValid() {
+			return req, ErrInvalidDiscount
+		}
+	}
+
+	items, err := mergeCheckoutItems(req.Items)
+	if err != nil {
+		return req, err
 	}
+	req.Items = items
+
+	return req, nil
+}
+
+// mergeCheckoutItems menggabungkan baris dengan product yang sama (urutan baris
+// pertama dipertahankan). Diskon fixed dijumlahkan, diskon percentage hanya bisa
+// digabung jika nilainya sama.
+func mergeCheckoutItems(items []models.CheckoutItem) ([]models.CheckoutItem, error) {
+	merged := make([]models.CheckoutItem, 0, len(items))
+	index := make(map[int]int)
+	var conflicts []models.CheckoutItemError
+
+	for _, item := range items {
+		i, ok := index[item.ProductID]
+		if !ok {
+			index[item.ProductID] = len(merged)
+			merged = append(merged, item)
+			continue
+		}
+
+		discount, ok := mergeDiscount(merged[i].Discount, item.Discount)
+		if !ok {
+			conflicts = append(conflicts, models.CheckoutItemError{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Reason:    models.CheckoutErrorConflictingDiscount,
+			})
+			continue
+		}
+
+		merged[i].Quantity += item.Quantity
+		merged[i].Discount = discount
+	}
+
+	if len(conflicts) > 0 {
+		return nil, &repository.CheckoutError{Items: conflicts}
+	}
+
+	return merged, nil
+}
+
+func mergeDiscount(a, b *models.Discount) (*models.Discount, bool) {
+	switch {
+	case a == nil && b == nil:
+		return nil, true
+	case a != nil && b != nil && *a == *b && a.Type == models.DiscountTypePercentage:
+		return a, true
+	case (a == nil || a.Type == models.DiscountTypeFixed) && (b == nil || b.Type == models.DiscountTypeFixed):
+		total := 0
+		if a != nil {
+			total += a.Value
+		}
+		if b != nil {
+			total += b.Value
+		}
+		return &models.Discount{Type: models.DiscountTypeFixed, Value: total}, true
+	}
+	return nil, false
+}
+
+// checkoutOptions menyiapkan promo aktif, pajak dan format invoice untuk repository
+func (s *TransactionService) checkoutOptions() (repository.CheckoutOptions, error) {
+	promotions, err := s.activePromotions(time.Now())
+	if err != nil {
+		return repository.CheckoutOptions{}, err
+	}
+
+	return repository.CheckoutOptions{
+		AdjustLines: func(details []models.TransactionDetail) {
+			applyPromotions(details, promotions)
+		},
+		Tax:     s.tax,
+		Invoice: s.invoice,
+	}, nil
 }
 
-func (s *TransactionService) Checkout(items []models.CheckoutItem) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+// activePromotions mengambil promo yang berlaku pada waktu now
+func (s *TransactionService) activePromotions(now time.Time) ([]models.Promotion, error) {
+	promotions, err := s.promotionRepo.GetActive(now)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]models.Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		if p.IsActiveAt(now) {
+			active = append(active, p)
+		}
+	}
+
+	return active, nil
+}
+
+func isValidPaymentMethod(method string) bool {
+	for _, m := range models.PaymentMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Get transaction history dengan filter dan pagination
+func (s *TransactionService) GetAll(filter models.TransactionFilter) (*models.TransactionList, error) {
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, ErrInvalidTransactionFilter
+	}
+	if filter.MinAmount > 0 && filter.MaxAmount > 0 && filter.MaxAmount < filter.MinAmount {
+		return nil, ErrInvalidTransactionFilter
+	}
+	if filter.Status != "" &&
+		filter.Status != models.TransactionStatusCompleted &&
+		filter.Status != models.TransactionStatusVoided {
+		return nil, ErrInvalidTransactionFilter
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultTransactionLimit
+	}
+	if filter.Limit > maxTransactionLimit {
+		filter.Limit = maxTransactionLimit
+	}
+
+	transactions, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TransactionList{
+		Data:  transactions,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
+
+// Get transaction by ID beserta detailnya
+func (s *TransactionService) GetByID(id int) (*models.Transaction, error) {
+	return s.repo.GetByID(id)
+}
+
+// Void transaksi, wajib mencatat siapa yang melakukan void dan alasannya
+func (s *TransactionService) Void(id int, req models.VoidRequest) (*models.Transaction, error) {
+	req.VoidedBy = strings.TrimSpace(req.VoidedBy)
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	if req.VoidedBy == "" || req.Reason == "" {
+		return nil, ErrInvalidVoidRequest
+	}
+
+	return s.repo.VoidTransaction(id, req)
 }
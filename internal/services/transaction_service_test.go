@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+)
+
+func percentage(v int) *models.Discount {
+	return &models.Discount{Type: models.DiscountTypePercentage, Value: v}
+}
+
+func fixed(v int) *models.Discount {
+	return &models.Discount{Type: models.DiscountTypeFixed, Value: v}
+}
+
+func TestMergeDiscount(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   *models.Discount
+		want   *models.Discount
+		wantOK bool
+	}{
+		{"both empty", nil, nil, nil, true},
+		{"same percentage", percentage(10), percentage(10), percentage(10), true},
+		{"different percentage", percentage(10), percentage(20), nil, false},
+		{"percentage and none", percentage(10), nil, nil, false},
+		{"none and percentage", nil, percentage(10), nil, false},
+		{"percentage and fixed", percentage(10), fixed(1000), nil, false},
+		{"fixed are summed", fixed(1000), fixed(500), fixed(1500), true},
+		{"fixed and none", fixed(1000), nil, fixed(1000), true},
+		{"none and fixed", nil, fixed(500), fixed(500), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mergeDiscount(tt.a, tt.b)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mergeDiscount(%v, %v) = %v, %v, want %v, %v", tt.a, tt.b, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMergeCheckoutItems(t *testing.T) {
+	tests := []struct {
+		name          string
+		items         []models.CheckoutItem
+		want          []models.CheckoutItem
+		wantConflicts []models.CheckoutItemError
+	}{
+		{
+			name: "distinct products keep their order",
+			items: []models.CheckoutItem{
+				{ProductID: 3, Quantity: 1},
+				{ProductID: 1, Quantity: 2},
+			},
+			want: []models.CheckoutItem{
+				{ProductID: 3, Quantity: 1},
+				{ProductID: 1, Quantity: 2},
+			},
+		},
+		{
+			name: "duplicate lines are merged at the first position",
+			items: []models.CheckoutItem{
+				{ProductID: 1, Quantity: 1},
+				{ProductID: 2, Quantity: 1},
+				{ProductID: 1, Quantity: 2, Discount: fixed(500)},
+				{ProductID: 1, Quantity: 1, Discount: fixed(300)},
+			},
+			want: []models.CheckoutItem{
+				{ProductID: 1, Quantity: 4, Discount: fixed(800)},
+				{ProductID: 2, Quantity: 1},
+			},
+		},
+		{
+			name: "same percentage discount merges",
+			items: []models.CheckoutItem{
+				{ProductID: 1, Quantity: 1, Discount: percentage(10)},
+				{ProductID: 1, Quantity: 1, Discount: percentage(10)},
+			},
+			want: []models.CheckoutItem{
+				{ProductID: 1, Quantity: 2, Discount: percentage(10)},
+			},
+		},
+		{
+			name: "conflicting discounts list every conflicting line",
+			items: []models.CheckoutItem{
+				{ProductID: 1, Quantity: 1, Discount: percentage(10)},
+				{ProductID: 1, Quantity: 2, Discount: percentage(20)},
+				{ProductID: 2, Quantity: 1, Discount: fixed(100)},
+				{ProductID: 2, Quantity: 3, Discount: percentage(5)},
+				{ProductID: 3, Quantity: 1},
+			},
+			wantConflicts: []models.CheckoutItemError{
+				{ProductID: 1, Quantity: 2, Reason: models.CheckoutErrorConflictingDiscount},
+				{ProductID: 2, Quantity: 3, Reason: models.CheckoutErrorConflictingDiscount},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeCheckoutItems(tt.items)
+
+			if tt.wantConflicts != nil {
+				var checkoutErr *repository.CheckoutError
+				if !errors.As(err, &checkoutErr) {
+					t.Fatalf("err = %v, want *repository.CheckoutError", err)
+				}
+				if !reflect.DeepEqual(checkoutErr.Items, tt.wantConflicts) {
+					t.Fatalf("conflicts = %+v, want %+v", checkoutErr.Items, tt.wantConflicts)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mergeCheckoutItems() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
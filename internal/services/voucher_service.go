@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/internal/models"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+var ErrInvalidVoucher = errors.New("voucher needs code, valid type/value and non-negative limits")
+
+type VoucherService struct {
+	repo *repository.VoucherRepository
+}
+
+func NewVoucherService(repo *repository.VoucherRepository) *VoucherService {
+	return &VoucherService{
+		repo: repo,
+	}
+}
+
+// Get all vouchers
+func (s *VoucherService) GetAll() ([]models.Voucher, error) {
+	return s.repo.GetAll()
+}
+
+// Get voucher by ID
+func (s *VoucherService) GetByID(id int) (models.Voucher, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create new voucher
+func (s *VoucherService) Create(voucher models.Voucher) (models.Voucher, error) {
+	if err := validateVoucher(&voucher); err != nil {
+		return models.Voucher{}, err
+	}
+
+	return s.repo.Create(voucher)
+}
+
+// Update voucher
+func (s *VoucherService) Update(id int, voucher models.Voucher) (models.Voucher, error) {
+	if err := validateVoucher(&voucher); err != nil {
+		return models.Voucher{}, err
+	}
+
+	return s.repo.Update(id, voucher)
+}
+
+// Delete voucher
+func (s *VoucherService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// Report penukaran per voucher
+func (s *VoucherService) GetRedemptionReport() ([]models.VoucherRedemptionReport, error) {
+	return s.repo.GetRedemptionReport()
+}
+
+// normalizeVoucherCode supaya kode tidak sensitif huruf besar/kecil
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateVoucher(voucher *models.Voucher) error {
+	voucher.Code = normalizeVoucherCode(voucher.Code)
+	if voucher.Code == "" {
+		return ErrInvalidVoucher
+	}
+
+	discount := models.Discount{Type: voucher.Type, Value: voucher.Value}
+	if !discount.IsValid() || voucher.Value == 0 {
+		return ErrInvalidVoucher
+	}
+
+	if voucher.MaxDiscount < 0 || voucher.MinPurchase < 0 ||
+		voucher.UsageLimit < 0 || voucher.PerCustomerLimit < 0 {
+		return ErrInvalidVoucher
+	}
+
+	return nil
+}
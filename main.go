@@ -4,16 +4,17 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
-	"os"
-	"strings"
+	"time"
 
-	"github.com/spf13/viper"
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	_ "kasir-api/docs"
+	"kasir-api/internal/config"
 	"kasir-api/internal/database"
 	"kasir-api/internal/middleware"
+	"kasir-api/internal/repository"
 	"kasir-api/internal/routes"
+	"kasir-api/internal/services"
 )
 
 // @title           kasir API
@@ -22,32 +23,11 @@ import (
 // @host            localhost:8081
 // @BasePath        /api/v1
 // @schemes         http
-type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
-}
-
 func main() {
-	// ===== VIPER SETUP =====
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-	// load .env if exists
-	if _, err := os.Stat(".env"); err == nil {
-		viper.SetConfigFile(".env")
-		viper.SetConfigType("env")
-		if err := viper.ReadInConfig(); err != nil {
-			log.Fatal("Error reading .env file:", err)
-		}
-	}
-
-	cfg := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
-	}
-
-	if cfg.Port == "" {
-		cfg.Port = "8081"
+	// ===== CONFIG =====
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Error loading config:", err)
 	}
 
 	// ===== DATABASE =====
@@ -64,7 +44,7 @@ func main() {
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
 
 	// api routes (inject DB)
-	routes.SetupRoutes(mux, db)
+	routes.SetupRoutes(mux, db, cfg)
 
 	// root handler
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -82,7 +62,17 @@ func main() {
 	})
 
 	// ===== MIDDLEWARE =====
-	handler := middleware.EnableCORS(mux)
+	idempotencyService := services.NewIdempotencyService(repository.NewIdempotencyRepository(db))
+	handler := middleware.EnableCORS(middleware.Idempotency(mux, idempotencyService))
+
+	// key idempotency yang kedaluwarsa dihapus berkala, bukan di setiap request
+	go func() {
+		for range time.Tick(time.Hour) {
+			if err := idempotencyService.PurgeExpired(); err != nil {
+				log.Println("failed to purge idempotency keys:", err)
+			}
+		}
+	}()
 
 	// ===== SERVER =====
 	log.Printf("Server running on port %s\n", cfg.Port)
@@ -0,0 +1,5 @@
+-- Relasi product ke category
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS category_id INT REFERENCES categories(id);
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
@@ -0,0 +1,20 @@
+-- Dokumen refund / retur yang terhubung ke transaksi asal
+CREATE TABLE IF NOT EXISTS refunds (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    total_amount   INT NOT NULL,
+    reason         TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS refund_details (
+    id                    SERIAL PRIMARY KEY,
+    refund_id             INT NOT NULL REFERENCES refunds(id),
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id),
+    product_id            INT NOT NULL,
+    quantity              INT NOT NULL CHECK (quantity > 0),
+    amount                INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_transaction_id ON refunds(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_refund_details_detail_id ON refund_details(transaction_detail_id);
@@ -0,0 +1,8 @@
+-- Status transaksi dan jejak void oleh supervisor
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS status      VARCHAR(20) NOT NULL DEFAULT 'completed',
+    ADD COLUMN IF NOT EXISTS voided_by   VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS void_reason TEXT,
+    ADD COLUMN IF NOT EXISTS voided_at   TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions(status);
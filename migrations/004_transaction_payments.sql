@@ -0,0 +1,13 @@
+-- Pembayaran per transaksi (bisa lebih dari satu metode)
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS paid_amount   INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS change_amount INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    method         VARCHAR(20) NOT NULL,
+    amount         INT NOT NULL CHECK (amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_transaction_id ON transaction_payments(transaction_id);
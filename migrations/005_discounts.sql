@@ -0,0 +1,24 @@
+-- Diskon per item dan per keranjang (gross - discount = net)
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS gross_amount    INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+
+UPDATE transaction_details SET gross_amount = subtotal WHERE gross_amount = 0;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS gross_amount         INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS discount_amount      INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cart_discount_amount INT NOT NULL DEFAULT 0;
+
+UPDATE transactions SET gross_amount = total_amount WHERE gross_amount = 0;
+
+-- constraint di-drop dulu supaya migration tetap bisa dijalankan ulang
+ALTER TABLE transaction_details
+    DROP CONSTRAINT IF EXISTS transaction_details_subtotal_non_negative;
+ALTER TABLE transaction_details
+    ADD CONSTRAINT transaction_details_subtotal_non_negative CHECK (subtotal >= 0);
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_total_amount_non_negative;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_total_amount_non_negative CHECK (total_amount >= 0);
@@ -0,0 +1,28 @@
+-- Promo otomatis: harga promo (happy hour), buy X get Y, dan bundle
+CREATE TABLE IF NOT EXISTS promotions (
+    id           SERIAL PRIMARY KEY,
+    name         VARCHAR(100) NOT NULL,
+    type         VARCHAR(20) NOT NULL,
+    product_id   INT REFERENCES products(id),
+    buy_qty      INT NOT NULL DEFAULT 0,
+    get_qty      INT NOT NULL DEFAULT 0,
+    promo_price  INT NOT NULL DEFAULT 0,
+    start_at     TIMESTAMP,
+    end_at       TIMESTAMP,
+    days_of_week INT[] NOT NULL DEFAULT '{}',
+    start_time   VARCHAR(5) NOT NULL DEFAULT '',
+    end_time     VARCHAR(5) NOT NULL DEFAULT '',
+    is_active    BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- isi bundle, hanya untuk type = 'bundle'
+CREATE TABLE IF NOT EXISTS promotion_items (
+    promotion_id INT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    product_id   INT NOT NULL REFERENCES products(id),
+    quantity     INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (promotion_id, product_id)
+);
+
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS promotion_id INT REFERENCES promotions(id);
@@ -0,0 +1,22 @@
+-- Voucher / kupon yang ditukar saat checkout
+CREATE TABLE IF NOT EXISTS vouchers (
+    id                 SERIAL PRIMARY KEY,
+    code               VARCHAR(50) NOT NULL UNIQUE,
+    type               VARCHAR(20) NOT NULL,
+    value              INT NOT NULL,
+    max_discount       INT NOT NULL DEFAULT 0,
+    min_purchase       INT NOT NULL DEFAULT 0,
+    usage_limit        INT NOT NULL DEFAULT 0,
+    per_customer_limit INT NOT NULL DEFAULT 0,
+    expires_at         TIMESTAMP,
+    is_active          BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at         TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- penukaran voucher dicatat langsung di transaksi
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS customer_ref            VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS voucher_id              INT REFERENCES vouchers(id),
+    ADD COLUMN IF NOT EXISTS voucher_discount_amount INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_voucher_id ON transactions(voucher_id);
@@ -0,0 +1,16 @@
+-- Kelas pajak per product dan komponen pajak / service charge per transaksi
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS tax_class VARCHAR(20) NOT NULL DEFAULT 'taxable';
+
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS tax_class             VARCHAR(20) NOT NULL DEFAULT 'taxable',
+    ADD COLUMN IF NOT EXISTS service_charge_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount            INT NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS service_charge_rate   NUMERIC(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS service_charge_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_rate              NUMERIC(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_inclusive         BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS taxable_amount        INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount            INT NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+-- Nomor invoice berurutan, counter per scope (prefix/outlet/tanggal atau bulan).
+-- Baris counter dikunci sampai transaksi commit sehingga nomor tidak loncat.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    scope       VARCHAR(100) PRIMARY KEY,
+    last_number INT NOT NULL DEFAULT 0
+);
+
+-- transaksi lama tidak diberi nomor (NULL)
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS invoice_number VARCHAR(100) UNIQUE;
@@ -0,0 +1,10 @@
+-- Response request POST yang memakai header Idempotency-Key, disimpan supaya
+-- retry dari client dengan key yang sama mendapat response yang sama
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key          VARCHAR(255) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code  INT,
+    content_type VARCHAR(100),
+    response     BYTEA,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,32 @@
+-- Keranjang yang di-hold (parkir) kasir untuk dilanjutkan nanti
+CREATE TABLE IF NOT EXISTS held_carts (
+    id             SERIAL PRIMARY KEY,
+    cashier        VARCHAR(100) NOT NULL,
+    label          VARCHAR(100) NOT NULL DEFAULT '',
+    discount_type  VARCHAR(20),
+    discount_value INT,
+    voucher_code   VARCHAR(50) NOT NULL DEFAULT '',
+    customer_ref   VARCHAR(100) NOT NULL DEFAULT '',
+    reserve_stock  BOOLEAN NOT NULL DEFAULT FALSE,
+    status         VARCHAR(20) NOT NULL DEFAULT 'held',
+    transaction_id INT REFERENCES transactions(id),
+    expires_at     TIMESTAMP NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS held_cart_items (
+    id             SERIAL PRIMARY KEY,
+    held_cart_id   INT NOT NULL REFERENCES held_carts(id) ON DELETE CASCADE,
+    product_id     INT NOT NULL REFERENCES products(id),
+    quantity       INT NOT NULL CHECK (quantity > 0),
+    discount_type  VARCHAR(20),
+    discount_value INT
+);
+
+-- label unik per kasir selama keranjang masih di-hold
+CREATE UNIQUE INDEX IF NOT EXISTS idx_held_carts_cashier_label
+    ON held_carts(cashier, label)
+    WHERE status = 'held' AND label <> '';
+
+CREATE INDEX IF NOT EXISTS idx_held_cart_items_product_id ON held_cart_items(product_id);
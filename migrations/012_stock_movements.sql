@@ -0,0 +1,22 @@
+-- Kartu stok: setiap perubahan products.stock dicatat di DB transaction yang sama.
+-- Kartu stok adalah audit trail: product yang sudah punya kartu stok tidak bisa dihapus.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id             SERIAL PRIMARY KEY,
+    product_id     INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    delta          INT NOT NULL,
+    balance        INT NOT NULL,
+    reason         VARCHAR(20) NOT NULL,
+    reference_type VARCHAR(30) NOT NULL DEFAULT '',
+    reference_id   INT,
+    created_by     VARCHAR(100) NOT NULL DEFAULT '',
+    note           TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, id);
+
+-- saldo awal untuk product yang sudah ada
+INSERT INTO stock_movements (product_id, delta, balance, reason, reference_type, reference_id, note)
+SELECT id, stock, stock, 'initial', 'product', id, 'opening balance'
+FROM products
+WHERE NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.product_id = products.id);
@@ -0,0 +1,13 @@
+-- Penyesuaian stok manual (rusak, hilang, ditemukan, kedaluwarsa),
+-- tiap baris punya pasangan stock_movements dengan reference_type 'stock_adjustment'
+CREATE TABLE IF NOT EXISTS stock_adjustments (
+    id         SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    delta      INT NOT NULL CHECK (delta <> 0),
+    reason     VARCHAR(20) NOT NULL,
+    note       TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_product_id ON stock_adjustments(product_id);
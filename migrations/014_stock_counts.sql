@@ -0,0 +1,27 @@
+-- Stock opname: sesi hitung fisik dengan snapshot stok sistem saat sesi dibuka
+CREATE TABLE IF NOT EXISTS stock_counts (
+    id         SERIAL PRIMARY KEY,
+    status     VARCHAR(20) NOT NULL DEFAULT 'open',
+    note       TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL,
+    posted_by  VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at  TIMESTAMP
+);
+
+-- hanya boleh ada satu sesi open
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_counts_open
+    ON stock_counts(status)
+    WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS stock_count_items (
+    stock_count_id INT NOT NULL REFERENCES stock_counts(id) ON DELETE CASCADE,
+    product_id     INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    system_stock   INT NOT NULL,
+    counted_qty    INT CHECK (counted_qty >= 0),
+    counted_by     VARCHAR(100) NOT NULL DEFAULT '',
+    counted_at     TIMESTAMP,
+    PRIMARY KEY (stock_count_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_count_items_product_id ON stock_count_items(product_id);
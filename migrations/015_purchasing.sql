@@ -0,0 +1,52 @@
+-- Supplier, purchase order dan penerimaan barang (goods receipt)
+CREATE TABLE IF NOT EXISTS suppliers (
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    phone      VARCHAR(50) NOT NULL DEFAULT '',
+    email      VARCHAR(255) NOT NULL DEFAULT '',
+    address    TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id          SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id),
+    status      VARCHAR(30) NOT NULL DEFAULT 'draft',
+    note        TEXT NOT NULL DEFAULT '',
+    created_by  VARCHAR(100) NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+
+-- satu baris per product dalam satu PO
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id        INT NOT NULL REFERENCES products(id),
+    quantity          INT NOT NULL CHECK (quantity > 0),
+    unit_cost         INT NOT NULL CHECK (unit_cost >= 0),
+    received_qty      INT NOT NULL DEFAULT 0 CHECK (received_qty >= 0 AND received_qty <= quantity),
+    UNIQUE (purchase_order_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id),
+    received_by       VARCHAR(100) NOT NULL,
+    note              TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_purchase_order_id ON goods_receipts(purchase_order_id);
+
+-- unit_cost adalah harga beli aktual saat barang diterima
+CREATE TABLE IF NOT EXISTS goods_receipt_lines (
+    id                     SERIAL PRIMARY KEY,
+    goods_receipt_id       INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_line_id INT NOT NULL REFERENCES purchase_order_lines(id),
+    product_id             INT NOT NULL REFERENCES products(id),
+    quantity               INT NOT NULL CHECK (quantity > 0),
+    unit_cost              INT NOT NULL CHECK (unit_cost >= 0)
+);
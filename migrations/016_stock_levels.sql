@@ -0,0 +1,7 @@
+-- Batas stok minimum, kelipatan order dan supplier utama per product
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS min_stock   INT NOT NULL DEFAULT 0 CHECK (min_stock >= 0),
+    ADD COLUMN IF NOT EXISTS reorder_qty INT NOT NULL DEFAULT 0 CHECK (reorder_qty >= 0),
+    ADD COLUMN IF NOT EXISTS supplier_id INT REFERENCES suppliers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_supplier_id ON products(supplier_id);
@@ -0,0 +1,7 @@
+-- Harga pokok product (rata-rata tertimbang, diperbarui saat penerimaan barang)
+-- dan snapshot harga pokok per unit di detail transaksi untuk laporan laba
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS cost_price INT NOT NULL DEFAULT 0 CHECK (cost_price >= 0);
+
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS unit_cost INT NOT NULL DEFAULT 0;
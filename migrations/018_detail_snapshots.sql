@@ -0,0 +1,15 @@
+-- Snapshot harga satuan dan nama product per baris detail, supaya histori
+-- dan laporan tidak berubah saat product di-rename atau harganya diubah
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS unit_price   INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- baris lama: harga satuan dari gross_amount, nama dari product saat ini (jika masih ada)
+UPDATE transaction_details
+SET unit_price = gross_amount / quantity
+WHERE unit_price = 0 AND quantity > 0;
+
+UPDATE transaction_details td
+SET product_name = p.name
+FROM products p
+WHERE p.id = td.product_id AND td.product_name = '';
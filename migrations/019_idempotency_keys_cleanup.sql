@@ -0,0 +1,2 @@
+-- Index untuk menghapus key yang sudah kedaluwarsa secara berkala (PurgeExpired)
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
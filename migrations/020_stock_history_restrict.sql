@@ -0,0 +1,16 @@
+-- Kartu stok, adjustment dan baris stock count tidak ikut terhapus bersama product
+-- (database yang sudah menjalankan 012-014 versi ON DELETE CASCADE)
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_product_id_fkey;
+ALTER TABLE stock_movements
+    ADD CONSTRAINT stock_movements_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+
+ALTER TABLE stock_adjustments DROP CONSTRAINT IF EXISTS stock_adjustments_product_id_fkey;
+ALTER TABLE stock_adjustments
+    ADD CONSTRAINT stock_adjustments_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+
+ALTER TABLE stock_count_items DROP CONSTRAINT IF EXISTS stock_count_items_product_id_fkey;
+ALTER TABLE stock_count_items
+    ADD CONSTRAINT stock_count_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
@@ -0,0 +1,4 @@
+-- Request yang masih diproses memperbarui heartbeat_at berkala, key in-flight hanya
+-- boleh diklaim ulang jika heartbeat-nya sudah berhenti (proses mati)
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP NOT NULL DEFAULT NOW();